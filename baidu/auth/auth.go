@@ -0,0 +1,43 @@
+package auth
+
+import (
+	"crypto/md5"
+	"encoding/hex"
+	"math/rand"
+	"strconv"
+)
+
+/*
+AddAuthParams 添加鉴权相关参数 -
+appid : 应用ID
+salt : 随机值
+sign : 请求签名
+@param appID     您的应用ID
+@param appKey    您的应用密钥
+@param params    请求参数表
+*/
+func AddAuthParams(appID string, appKey string, params map[string][]string) {
+	var q string
+	for _, v := range params["q"] {
+		q += v
+	}
+	salt := strconv.Itoa(rand.Intn(1000000000))
+	params["appid"] = []string{appID}
+	params["salt"] = []string{salt}
+	params["sign"] = []string{CalculateSign(appID, appKey, q, salt)}
+}
+
+/*
+CalculateSign 计算签名 -
+计算方式 : sign = md5(appid + q + salt + appKey), q为UTF-8编码且未经URL编码的原文
+
+@param appID     您的应用ID
+@param appKey    您的应用密钥
+@param q         请求内容
+@param salt      随机值
+@return 32位小写的鉴权签名sign
+*/
+func CalculateSign(appID string, appKey string, q string, salt string) string {
+	sum := md5.Sum([]byte(appID + q + salt + appKey))
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,36 @@
+package auth
+
+import "testing"
+
+func TestCalculateSign(t *testing.T) {
+	tests := []struct {
+		appID, appKey, q, salt string
+		want                   string
+	}{
+		// Example from the Baidu Fanyi API documentation
+		{"2015063000000001", "12345678", "apple", "1435660288", "f89f9594663708c1605f3d736d01d2d4"},
+	}
+	for _, tt := range tests {
+		if got := CalculateSign(tt.appID, tt.appKey, tt.q, tt.salt); got != tt.want {
+			t.Errorf("CalculateSign(%q, %q, %q, %q) = %s, want %s", tt.appID, tt.appKey, tt.q, tt.salt, got, tt.want)
+		}
+	}
+}
+
+func TestAddAuthParams(t *testing.T) {
+	params := map[string][]string{
+		"q":    {"apple"},
+		"from": {"en"},
+		"to":   {"zh"},
+	}
+	AddAuthParams("2015063000000001", "12345678", params)
+
+	if got := params["appid"]; len(got) != 1 || got[0] != "2015063000000001" {
+		t.Fatalf("appid = %v", got)
+	}
+	salt := params["salt"][0]
+	want := CalculateSign("2015063000000001", "12345678", "apple", salt)
+	if got := params["sign"][0]; got != want {
+		t.Errorf("sign = %s, want %s", got, want)
+	}
+}
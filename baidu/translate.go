@@ -0,0 +1,136 @@
+package baidu
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/yangxin0/gd-website-api/baidu/auth"
+	"github.com/yangxin0/gd-website-api/translator"
+	"gopkg.in/ini.v1"
+)
+
+const apiURL = "https://fanyi-api.baidu.com/api/trans/vip/translate"
+
+func init() {
+	translator.Register(translator.Provider{
+		Name:  "baidu",
+		Title: "Baidu",
+		New:   New,
+	})
+}
+
+// dialect maps canonical codes to Baidu Fanyi codes, which are mostly three
+// letter abbreviations of the language name.
+var dialect = translator.Dialect{
+	Codes: map[string]string{
+		"zh":    "zh",
+		"zh-TW": "cht",
+		"ja":    "jp",
+		"ko":    "kor",
+		"fr":    "fra",
+		"es":    "spa",
+		"ar":    "ara",
+		"vi":    "vie",
+		"bg":    "bul",
+		"et":    "est",
+		"da":    "dan",
+		"fi":    "fin",
+		"ro":    "rom",
+		"sl":    "slo",
+		"sv":    "swe",
+	},
+	Default: func(lang string) string {
+		return strings.SplitN(lang, "-", 2)[0]
+	},
+}
+
+// Translation is the response of the general translation API.
+type Translation struct {
+	ErrorCode   string `json:"error_code"`
+	ErrorMsg    string `json:"error_msg"`
+	From        string `json:"from"`
+	To          string `json:"to"`
+	TransResult []struct {
+		Src string `json:"src"`
+		Dst string `json:"dst"`
+	} `json:"trans_result"`
+}
+
+// Translator uses the Baidu Fanyi general translation API.
+type Translator struct {
+	appID     string
+	appSecret string
+	client    *http.Client
+}
+
+func New(cfg *ini.Section) (translator.Translator, error) {
+	appID := cfg.Key("app_id").String()
+	appSecret := cfg.Key("app_secret").String()
+	if appID == "" || appSecret == "" {
+		return nil, fmt.Errorf("app_id and app_secret are required")
+	}
+	return &Translator{
+		appID:     appID,
+		appSecret: appSecret,
+		client:    &http.Client{Timeout: 10 * time.Second},
+	}, nil
+}
+
+func (t *Translator) Translate(ctx context.Context, req translator.Request) (translator.Result, error) {
+	from := dialect.Code(req.SourceLang)
+	if from == "" {
+		from = "auto"
+	}
+	result, err := t.translate(ctx, from, dialect.Code(req.TargetLang), req.Text)
+	if err != nil {
+		return translator.Result{}, err
+	}
+	var lines []string
+	for _, r := range result.TransResult {
+		lines = append(lines, r.Dst)
+	}
+	return translator.Result{
+		Text:       strings.Join(lines, "\n"),
+		SourceLang: dialect.Canonical(result.From),
+		TargetLang: dialect.Canonical(result.To),
+	}, nil
+}
+
+func (t *Translator) translate(ctx context.Context, from, to, text string) (*Translation, error) {
+	params := map[string][]string{
+		"q":    {text},
+		"from": {from},
+		"to":   {to},
+	}
+	auth.AddAuthParams(t.appID, t.appSecret, params)
+
+	request, err := http.NewRequestWithContext(ctx, "POST", apiURL, strings.NewReader(url.Values(params).Encode()))
+	if err != nil {
+		return nil, err
+	}
+	request.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	resp, err := t.client.Do(request)
+	if err != nil {
+		return nil, translator.Errorf("Baidu API request failed: %v", err)
+	}
+	defer resp.Body.Close()
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, translator.Errorf("Baidu API request failed: %v", err)
+	}
+
+	var result Translation
+	if err := json.Unmarshal(data, &result); err != nil {
+		return nil, translator.Errorf("Baidu API returned invalid JSON: %v", err)
+	}
+	if result.ErrorCode != "" && result.ErrorCode != "52000" {
+		return nil, translator.Errorf("Baidu API error %s: %s", result.ErrorCode, result.ErrorMsg)
+	}
+	return &result, nil
+}
@@ -0,0 +1,258 @@
+package bing
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/yangxin0/gd-website-api/translator"
+	"gopkg.in/ini.v1"
+)
+
+const apiURL = "https://api.cognitive.microsofttranslator.com"
+
+func init() {
+	translator.Register(translator.Provider{
+		Name:  "bing",
+		Title: "Bing",
+		New:   New,
+	})
+}
+
+// dialect maps canonical codes to Azure Translator codes, which use script
+// subtags for Chinese.
+var dialect = translator.Dialect{
+	Codes: map[string]string{
+		"zh":    "zh-Hans",
+		"zh-TW": "zh-Hant",
+		"pt":    "pt-pt",
+		"pt-BR": "pt",
+	},
+}
+
+type textItem struct {
+	Text string `json:"Text"`
+}
+
+// Translation is one element of the /translate response.
+type Translation struct {
+	DetectedLanguage *struct {
+		Language string  `json:"language"`
+		Score    float64 `json:"score"`
+	} `json:"detectedLanguage"`
+	Translations []struct {
+		Text string `json:"text"`
+		To   string `json:"to"`
+	} `json:"translations"`
+}
+
+// Lookup is one element of the /dictionary/lookup response.
+type Lookup struct {
+	NormalizedSource string `json:"normalizedSource"`
+	DisplaySource    string `json:"displaySource"`
+	Translations     []struct {
+		NormalizedTarget string  `json:"normalizedTarget"`
+		DisplayTarget    string  `json:"displayTarget"`
+		PosTag           string  `json:"posTag"`
+		Confidence       float64 `json:"confidence"`
+		BackTranslations []struct {
+			DisplayText string `json:"displayText"`
+		} `json:"backTranslations"`
+	} `json:"translations"`
+}
+
+// Examples is one element of the /dictionary/examples response.
+type Examples struct {
+	Examples []struct {
+		SourcePrefix string `json:"sourcePrefix"`
+		SourceTerm   string `json:"sourceTerm"`
+		SourceSuffix string `json:"sourceSuffix"`
+		TargetPrefix string `json:"targetPrefix"`
+		TargetTerm   string `json:"targetTerm"`
+		TargetSuffix string `json:"targetSuffix"`
+	} `json:"examples"`
+}
+
+// Translator uses Azure AI Translator (Microsoft/Bing Translator), with the
+// dictionary lookup and examples endpoints for single words.
+type Translator struct {
+	key    string
+	region string
+	client *http.Client
+}
+
+func New(cfg *ini.Section) (translator.Translator, error) {
+	key := cfg.Key("app_secret").String()
+	if key == "" {
+		return nil, fmt.Errorf("app_secret is required")
+	}
+	return &Translator{
+		key:    key,
+		region: cfg.Key("region").String(),
+		client: &http.Client{Timeout: 10 * time.Second},
+	}, nil
+}
+
+// post sends body to an API path and decodes the JSON answer into v.
+func (t *Translator) post(ctx context.Context, path string, params url.Values, body any, v any) error {
+	params.Set("api-version", "3.0")
+	data, err := json.Marshal(body)
+	if err != nil {
+		return err
+	}
+	request, err := http.NewRequestWithContext(ctx, "POST", apiURL+path+"?"+params.Encode(), bytes.NewReader(data))
+	if err != nil {
+		return err
+	}
+	request.Header.Set("Content-Type", "application/json")
+	request.Header.Set("Ocp-Apim-Subscription-Key", t.key)
+	if t.region != "" {
+		request.Header.Set("Ocp-Apim-Subscription-Region", t.region)
+	}
+
+	resp, err := t.client.Do(request)
+	if err != nil {
+		return translator.Errorf("Bing API request failed: %v", err)
+	}
+	defer resp.Body.Close()
+	data, err = io.ReadAll(resp.Body)
+	if err != nil {
+		return translator.Errorf("Bing API request failed: %v", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return &translator.Error{Code: resp.StatusCode, Message: "Bing API error: " + string(data)}
+	}
+	return json.Unmarshal(data, v)
+}
+
+func (t *Translator) Translate(ctx context.Context, req translator.Request) (translator.Result, error) {
+	results, err := t.TranslateBatch(ctx, req, []string{req.Text})
+	if err != nil {
+		return translator.Result{}, err
+	}
+	result := results[0]
+	if translator.IsWord(req.Text) && result.SourceLang != "" {
+		result.Entry = t.lookup(ctx, req.Text, result.SourceLang, req.TargetLang)
+	}
+	return result, nil
+}
+
+// TranslateBatch translates all texts with a single /translate call.
+func (t *Translator) TranslateBatch(ctx context.Context, req translator.Request, texts []string) ([]translator.Result, error) {
+	params := url.Values{"to": {dialect.Code(req.TargetLang)}}
+	if req.SourceLang != "" {
+		params.Set("from", dialect.Code(req.SourceLang))
+	}
+	if req.Format == "html" {
+		params.Set("textType", "html")
+	}
+	body := make([]textItem, len(texts))
+	for i, text := range texts {
+		body[i].Text = text
+	}
+
+	var translations []Translation
+	if err := t.post(ctx, "/translate", params, body, &translations); err != nil {
+		return nil, err
+	}
+	if len(translations) != len(texts) {
+		return nil, translator.Errorf("Translation failed, API returns an incomplete result.")
+	}
+
+	results := make([]translator.Result, len(texts))
+	for i, translation := range translations {
+		results[i] = translator.Result{
+			SourceLang: req.SourceLang,
+			TargetLang: req.TargetLang,
+		}
+		if len(translation.Translations) > 0 {
+			results[i].Text = translation.Translations[0].Text
+		}
+		if results[i].SourceLang == "" && translation.DetectedLanguage != nil {
+			results[i].SourceLang = dialect.Canonical(translation.DetectedLanguage.Language)
+		}
+	}
+	return results, nil
+}
+
+// Detect uses the /detect endpoint.
+func (t *Translator) Detect(ctx context.Context, text string) (string, float64, error) {
+	var detections []struct {
+		Language string  `json:"language"`
+		Score    float64 `json:"score"`
+	}
+	if err := t.post(ctx, "/detect", url.Values{}, []textItem{{Text: text}}, &detections); err != nil {
+		return "", 0, err
+	}
+	if len(detections) == 0 {
+		return "", 0, translator.ErrNoTranslation
+	}
+	return dialect.Canonical(detections[0].Language), detections[0].Score, nil
+}
+
+// lookup builds a dictionary entry from the back-translations of the word
+// and example sentences for its most confident translations. Dictionary
+// failures only drop the entry; the plain translation is still returned.
+func (t *Translator) lookup(ctx context.Context, word string, sourceLang string, targetLang string) *translator.Entry {
+	params := url.Values{
+		"from": {dialect.Code(sourceLang)},
+		"to":   {dialect.Code(targetLang)},
+	}
+	var lookups []Lookup
+	if err := t.post(ctx, "/dictionary/lookup", params, []textItem{{Text: word}}, &lookups); err != nil {
+		return nil
+	}
+	if len(lookups) == 0 || len(lookups[0].Translations) == 0 {
+		return nil
+	}
+
+	lookup := lookups[0]
+	entry := &translator.Entry{Word: lookup.DisplaySource}
+	type exampleItem struct {
+		Text        string `json:"Text"`
+		Translation string `json:"Translation"`
+	}
+	var pairs []exampleItem
+	for _, translation := range lookup.Translations {
+		var back []string
+		for _, b := range translation.BackTranslations {
+			back = append(back, b.DisplayText)
+		}
+		entry.Senses = append(entry.Senses, translator.Sense{
+			PartOfSpeech: strings.ToLower(translation.PosTag),
+			Meaning:      strings.Join(back, ", "),
+			Translations: []string{translation.DisplayTarget},
+		})
+		// The examples endpoint accepts up to 10 pairs per request
+		if len(pairs) < 10 {
+			pairs = append(pairs, exampleItem{Text: lookup.NormalizedSource, Translation: translation.NormalizedTarget})
+		}
+	}
+
+	var examples []Examples
+	if err := t.post(ctx, "/dictionary/examples", params, pairs, &examples); err != nil {
+		return entry
+	}
+	for i := range examples {
+		if i >= len(entry.Senses) {
+			break
+		}
+		for j, e := range examples[i].Examples {
+			// Two examples per sense keep the popup short
+			if j == 2 {
+				break
+			}
+			entry.Senses[i].Examples = append(entry.Senses[i].Examples, translator.Example{
+				Text:        e.SourcePrefix + e.SourceTerm + e.SourceSuffix,
+				Translation: e.TargetPrefix + e.TargetTerm + e.TargetSuffix,
+			})
+		}
+	}
+	return entry
+}
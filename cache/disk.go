@@ -0,0 +1,172 @@
+package cache
+
+import (
+	"encoding/binary"
+	"sync/atomic"
+	"time"
+
+	bolt "go.etcd.io/bbolt"
+)
+
+var (
+	entriesBucket = []byte("entries")
+	indexBucket   = []byte("index")
+)
+
+// Disk is a persistent cache stored in a bbolt file. Entries expire after a
+// TTL and the oldest entries are evicted once the cache holds more than
+// size entries.
+type Disk struct {
+	db     *bolt.DB
+	size   int
+	ttl    time.Duration
+	count  atomic.Int64
+	hits   atomic.Uint64
+	misses atomic.Uint64
+}
+
+// OpenDisk opens or creates the cache file at path. A zero size or ttl
+// disables eviction or expiry respectively.
+func OpenDisk(path string, size int, ttl time.Duration) (*Disk, error) {
+	db, err := bolt.Open(path, 0600, &bolt.Options{Timeout: time.Second})
+	if err != nil {
+		return nil, err
+	}
+	d := &Disk{db: db, size: size, ttl: ttl}
+	err = db.Update(func(tx *bolt.Tx) error {
+		entries, err := tx.CreateBucketIfNotExists(entriesBucket)
+		if err != nil {
+			return err
+		}
+		if _, err := tx.CreateBucketIfNotExists(indexBucket); err != nil {
+			return err
+		}
+		d.count.Store(int64(entries.Stats().KeyN))
+		return nil
+	})
+	if err != nil {
+		db.Close()
+		return nil, err
+	}
+	return d, nil
+}
+
+// Close releases the cache file.
+func (d *Disk) Close() error {
+	return d.db.Close()
+}
+
+// Get returns the value stored for key. Expired entries are removed.
+func (d *Disk) Get(key string) ([]byte, bool) {
+	var value []byte
+	var created int64
+	d.db.View(func(tx *bolt.Tx) error {
+		record := tx.Bucket(entriesBucket).Get([]byte(key))
+		if len(record) >= 8 {
+			created = int64(binary.BigEndian.Uint64(record))
+			value = append([]byte(nil), record[8:]...)
+		}
+		return nil
+	})
+	if value == nil {
+		d.misses.Add(1)
+		return nil, false
+	}
+	if d.ttl > 0 && time.Since(time.Unix(0, created)) > d.ttl {
+		d.db.Update(func(tx *bolt.Tx) error {
+			return d.delete(tx, []byte(key))
+		})
+		d.misses.Add(1)
+		return nil, false
+	}
+	d.hits.Add(1)
+	return value, true
+}
+
+// Set stores value for key, evicting the oldest entries when the cache is
+// full.
+func (d *Disk) Set(key string, value []byte) error {
+	return d.db.Update(func(tx *bolt.Tx) error {
+		if err := d.delete(tx, []byte(key)); err != nil {
+			return err
+		}
+		created := make([]byte, 8)
+		binary.BigEndian.PutUint64(created, uint64(time.Now().UnixNano()))
+		record := append(created, value...)
+		if err := tx.Bucket(entriesBucket).Put([]byte(key), record); err != nil {
+			return err
+		}
+		if err := tx.Bucket(indexBucket).Put(indexKey(created, []byte(key)), nil); err != nil {
+			return err
+		}
+		d.count.Add(1)
+
+		cursor := tx.Bucket(indexBucket).Cursor()
+		for d.size > 0 && d.count.Load() > int64(d.size) {
+			k, _ := cursor.First()
+			if k == nil {
+				break
+			}
+			if err := d.delete(tx, k[8:]); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// Purge removes every entry whose key matches and returns how many were
+// removed.
+func (d *Disk) Purge(match func(key string) bool) (int, error) {
+	removed := 0
+	err := d.db.Update(func(tx *bolt.Tx) error {
+		var keys [][]byte
+		tx.Bucket(entriesBucket).ForEach(func(k, v []byte) error {
+			if match(string(k)) {
+				keys = append(keys, append([]byte(nil), k...))
+			}
+			return nil
+		})
+		for _, k := range keys {
+			if err := d.delete(tx, k); err != nil {
+				return err
+			}
+			removed++
+		}
+		return nil
+	})
+	return removed, err
+}
+
+// Stats returns the current hit/miss counters and entry count.
+func (d *Disk) Stats() Stats {
+	return Stats{
+		Hits:    d.hits.Load(),
+		Misses:  d.misses.Load(),
+		Entries: int(d.count.Load()),
+		Size:    d.size,
+	}
+}
+
+// delete removes key and its index entry if present.
+func (d *Disk) delete(tx *bolt.Tx, key []byte) error {
+	entries := tx.Bucket(entriesBucket)
+	record := entries.Get(key)
+	if len(record) < 8 {
+		return nil
+	}
+	if err := tx.Bucket(indexBucket).Delete(indexKey(record[:8], key)); err != nil {
+		return err
+	}
+	if err := entries.Delete(key); err != nil {
+		return err
+	}
+	d.count.Add(-1)
+	return nil
+}
+
+// indexKey orders entries by creation time for eviction.
+func indexKey(created []byte, key []byte) []byte {
+	k := make([]byte, 0, len(created)+len(key))
+	return append(append(k, created...), key...)
+}
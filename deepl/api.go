@@ -0,0 +1,173 @@
+package deepl
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"log"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+)
+
+// statusQuotaExceeded is returned by the official API once the character
+// quota of an auth key is used up.
+const statusQuotaExceeded = 456
+
+// usageInterval is how long the /v2/usage answer for a key is trusted.
+const usageInterval = 10 * time.Minute
+
+// apiClient is used for every official API call, so that a hung request
+// cannot block a lookup forever.
+var apiClient = &http.Client{Timeout: 10 * time.Second}
+
+type authKey struct {
+	key       string
+	available bool
+	checked   time.Time
+}
+
+// keyPool rotates through official API auth keys, skipping keys whose
+// character quota is used up.
+type keyPool struct {
+	mu      sync.Mutex
+	keys    []*authKey
+	current int
+}
+
+func newKeyPool(keys []string) *keyPool {
+	p := &keyPool{}
+	for _, key := range keys {
+		p.keys = append(p.keys, &authKey{key: key})
+	}
+	return p
+}
+
+// pick returns the first key, starting at the one last used, that still has
+// quota left according to /v2/usage. The usage is checked without holding
+// the lock, so a slow check does not block lookups using other keys.
+func (p *keyPool) pick(ctx context.Context) (string, bool) {
+	p.mu.Lock()
+	current, count := p.current, len(p.keys)
+	p.mu.Unlock()
+
+	for i := 0; i < count; i++ {
+		index := (current + i) % count
+		p.mu.Lock()
+		k := p.keys[index]
+		stale := time.Since(k.checked) > usageInterval
+		available := k.available
+		p.mu.Unlock()
+
+		if stale {
+			var err error
+			available, err = checkUsageAuthKey(ctx, k.key)
+			if ctx.Err() != nil {
+				return "", false
+			}
+			if err != nil {
+				log.Println(err)
+			}
+			available = err == nil && available
+			p.mu.Lock()
+			k.available = available
+			k.checked = time.Now()
+			p.mu.Unlock()
+		}
+		if available {
+			p.mu.Lock()
+			p.current = index
+			p.mu.Unlock()
+			return k.key, true
+		}
+	}
+	return "", false
+}
+
+// exhaust marks key as out of quota until its usage is checked again.
+func (p *keyPool) exhaust(key string) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	for _, k := range p.keys {
+		if k.key == key {
+			k.available = false
+			k.checked = time.Now()
+		}
+	}
+}
+
+// TranslateAPI translates text with the official DeepL API (Free or Pro,
+// depending on authKey).
+func TranslateAPI(ctx context.Context, authKey string, sourceLang string, targetLang string, translateText string) (DeepLXTranslationResult, error) {
+	if translateText == "" {
+		return DeepLXTranslationResult{
+			Code:    http.StatusNotFound,
+			Message: "No text to translate",
+		}, nil
+	}
+	if targetLang == "" {
+		targetLang = "EN"
+	}
+
+	// The official API only accepts regional variants for the target language
+	payload := PayloadAPI{
+		Text:       []string{translateText},
+		TargetLang: targetLang,
+		SourceLang: strings.Split(sourceLang, "-")[0],
+	}
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return DeepLXTranslationResult{}, err
+	}
+
+	request, err := http.NewRequestWithContext(ctx, "POST", apiURL(authKey, "/v2/translate"), bytes.NewReader(body))
+	if err != nil {
+		return DeepLXTranslationResult{}, err
+	}
+	request.Header.Set("Content-Type", "application/json")
+	request.Header.Set("Authorization", "DeepL-Auth-Key "+authKey)
+
+	resp, err := apiClient.Do(request)
+	if err != nil {
+		return DeepLXTranslationResult{}, err
+	}
+	defer resp.Body.Close()
+
+	body, err = io.ReadAll(resp.Body)
+	if err != nil {
+		return DeepLXTranslationResult{}, err
+	}
+	if resp.StatusCode != http.StatusOK {
+		return DeepLXTranslationResult{
+			Code:    resp.StatusCode,
+			Message: string(body),
+		}, nil
+	}
+
+	var response TranslationResponse
+	if err := json.Unmarshal(body, &response); err != nil {
+		return DeepLXTranslationResult{}, err
+	}
+	if len(response.Translations) == 0 || response.Translations[0].Text == "" {
+		return DeepLXTranslationResult{
+			Code:    http.StatusServiceUnavailable,
+			Message: "Translation failed, API returns an empty result.",
+		}, nil
+	}
+
+	translation := response.Translations[0]
+	if sourceLang == "" {
+		sourceLang = translation.DetectedSourceLanguage
+	}
+	return DeepLXTranslationResult{
+		Code:       http.StatusOK,
+		Message:    "Success",
+		Data:       translation.Text,
+		SourceLang: sourceLang,
+		TargetLang: targetLang,
+		Method:     "Official",
+	}, nil
+}
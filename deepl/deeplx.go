@@ -0,0 +1,68 @@
+package deepl
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/yangxin0/gd-website-api/translator"
+)
+
+// PayloadFree is the body of the DeepLX compatible POST /translate.
+type PayloadFree struct {
+	TransText  string `json:"text"`
+	SourceLang string `json:"source_lang"`
+	TargetLang string `json:"target_lang"`
+}
+
+// RootRoutes serves the DeepLX protocol on POST /translate, so DeepLX
+// scripts and browser extensions can use this server.
+func (t *Translator) RootRoutes(route *gin.Engine) {
+	route.POST("/translate", t.deeplxHandler)
+}
+
+func (t *Translator) deeplxHandler(c *gin.Context) {
+	if t.token != "" && c.GetHeader("Authorization") != "Bearer "+t.token {
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"code":    http.StatusUnauthorized,
+			"message": "Invalid access token",
+		})
+		return
+	}
+
+	var req PayloadFree
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"code":    http.StatusBadRequest,
+			"message": "Invalid request: " + err.Error(),
+		})
+		return
+	}
+
+	// Accept canonical codes as well as DeepL codes such as "ZH" or "EN-US".
+	sourceLang := dialect.Code(translator.Normalize(req.SourceLang))
+	targetLang := dialect.Code(translator.Normalize(req.TargetLang))
+	result, err := t.translate(c.Request.Context(), sourceLang, targetLang, req.TransText)
+	if err != nil {
+		c.JSON(http.StatusServiceUnavailable, gin.H{
+			"code":    http.StatusServiceUnavailable,
+			"message": err.Error(),
+		})
+		return
+	}
+	if result.Code != http.StatusOK {
+		c.JSON(result.Code, gin.H{
+			"code":    result.Code,
+			"message": result.Message,
+		})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"code":         http.StatusOK,
+		"id":           result.ID,
+		"data":         result.Data,
+		"alternatives": result.Alternatives,
+		"source_lang":  result.SourceLang,
+		"target_lang":  result.TargetLang,
+		"method":       result.Method,
+	})
+}
@@ -2,8 +2,8 @@ package deepl
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
-	"fmt"
 	"io"
 	"log"
 	"net/http"
@@ -11,8 +11,8 @@ import (
 
 	"github.com/abadojack/whatlanggo"
 	"github.com/andybalholm/brotli"
-	"github.com/gin-gonic/gin"
 	"github.com/tidwall/gjson"
+	"github.com/yangxin0/gd-website-api/translator"
 	"gopkg.in/ini.v1"
 )
 
@@ -50,11 +50,12 @@ type PostData struct {
 type PayloadAPI struct {
 	Text       []string `json:"text"`
 	TargetLang string   `json:"target_lang"`
-	SourceLang string   `json:"source_lang"`
+	SourceLang string   `json:"source_lang,omitempty"`
 }
 
 type Translation struct {
-	Text string `json:"text"`
+	DetectedSourceLanguage string `json:"detected_source_language"`
+	Text                   string `json:"text"`
 }
 
 type TranslationResponse struct {
@@ -109,32 +110,76 @@ func initDeepLXData(sourceLang string, targetLang string) *PostData {
 	}
 }
 
-// DeepL free account API
-func TranslateInit(route *gin.Engine, cfg *ini.File) {
-    enabled := cfg.Section("deepl").Key("enable").MustBool()
-    if enabled == false {
-        fmt.Println("Dict: DeepL Disabled")
-        return
-    }
-    fmt.Println("Dict: DeepL Enabled")
-    route.GET("/deepl", func(c *gin.Context) {
-        sourceLang := ""
-        targetLang := "ZH"
-        translateText := c.Query("gdword")
-
-        result, err := Translate(sourceLang, targetLang, translateText)
-        if err != nil {
-            log.Fatalf("Translation failed: %s", err)
-        }
-
-        if result.Code == http.StatusOK {
-            c.HTML(http.StatusOK, "goldendict.tmpl", gin.H{
-                "Text": result.Data,
-            })
-        } else {
-            c.String(result.Code, result.Message)
-        }
-    })
+func init() {
+	translator.Register(translator.Provider{
+		Name:  "deepl",
+		Title: "DeepL",
+		New:   New,
+	})
+}
+
+// dialect maps canonical codes to DeepL's upper case codes, e.g. "ZH" and
+// "PT-BR".
+var dialect = translator.Dialect{
+	Codes: map[string]string{
+		"zh-TW": "ZH-HANT",
+	},
+	Default: strings.ToUpper,
+}
+
+// Translator uses the official DeepL API while one of the configured auth
+// keys has quota left, and the DeepL free account API otherwise.
+type Translator struct {
+	keys *keyPool
+	// token protects the DeepLX compatible POST /translate when set.
+	token string
+}
+
+func New(cfg *ini.Section) (translator.Translator, error) {
+	t := &Translator{token: cfg.Key("token").String()}
+	if keys := cfg.Key("auth_keys").Strings(","); len(keys) > 0 {
+		t.keys = newKeyPool(keys)
+	}
+	return t, nil
+}
+
+func (t *Translator) Translate(ctx context.Context, req translator.Request) (translator.Result, error) {
+	result, err := t.translate(ctx, dialect.Code(req.SourceLang), dialect.Code(req.TargetLang), req.Text)
+	if err != nil {
+		return translator.Result{}, err
+	}
+	if result.Code != http.StatusOK {
+		return translator.Result{}, &translator.Error{Code: result.Code, Message: result.Message}
+	}
+	return toResult(result), nil
+}
+
+// translate tries the official API keys in turn and falls back to the free
+// API. The languages are DeepL codes.
+func (t *Translator) translate(ctx context.Context, sourceLang string, targetLang string, text string) (DeepLXTranslationResult, error) {
+	if t.keys != nil {
+		for authKey, ok := t.keys.pick(ctx); ok; authKey, ok = t.keys.pick(ctx) {
+			result, err := TranslateAPI(ctx, authKey, sourceLang, targetLang, text)
+			if err == nil && result.Code == http.StatusOK {
+				return result, nil
+			}
+			if err != nil || (result.Code != http.StatusForbidden && result.Code != statusQuotaExceeded) {
+				log.Printf("DeepL API failed, falling back to free API: %v %v", err, result.Message)
+				break
+			}
+			t.keys.exhaust(authKey)
+		}
+	}
+	return Translate(sourceLang, targetLang, text)
+}
+
+func toResult(result DeepLXTranslationResult) translator.Result {
+	return translator.Result{
+		Text:         result.Data,
+		Alternatives: result.Alternatives,
+		SourceLang:   dialect.Canonical(result.SourceLang),
+		TargetLang:   dialect.Canonical(result.TargetLang),
+	}
 }
 
 func Translate(sourceLang string, targetLang string, translateText string) (DeepLXTranslationResult, error) {
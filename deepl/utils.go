@@ -1,6 +1,7 @@
 package deepl
 
 import (
+	"context"
 	"encoding/json"
 	"io"
 	"math/rand"
@@ -30,17 +31,25 @@ func getTimeStamp(iCount int64) int64 {
 	}
 }
 
-func checkUsageAuthKey(authKey string) (bool, error) {
-	url := "https://api-free.deepl.com/v2/usage"
-	req, err := http.NewRequest("GET", url, nil)
+// apiURL returns the official API endpoint for authKey. Keys of free
+// accounts end with ":fx" and must use api-free.deepl.com.
+func apiURL(authKey string, path string) string {
+	if strings.HasSuffix(authKey, ":fx") {
+		return "https://api-free.deepl.com" + path
+	}
+	return "https://api.deepl.com" + path
+}
+
+func checkUsageAuthKey(ctx context.Context, authKey string) (bool, error) {
+	url := apiURL(authKey, "/v2/usage")
+	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
 	if err != nil {
 		return false, err
 	}
 
 	req.Header.Add("Authorization", "DeepL-Auth-Key "+authKey)
 
-	client := &http.Client{}
-	resp, err := client.Do(req)
+	resp, err := apiClient.Do(req)
 	if err != nil {
 		return false, err
 	}
@@ -56,5 +65,5 @@ func checkUsageAuthKey(authKey string) (bool, error) {
 	if err != nil {
 		return false, err
 	}
-	return response.CharacterCount < 499900, nil
+	return response.CharacterCount+100 < response.CharacterLimit, nil
 }
@@ -6,51 +6,105 @@ import (
 	"net/http"
 
 	"cloud.google.com/go/translate"
-	"github.com/gin-gonic/gin"
+	"github.com/yangxin0/gd-website-api/translator"
 	"golang.org/x/text/language"
 	"google.golang.org/api/option"
 	"gopkg.in/ini.v1"
 )
 
-var appSecret = ""
-
-func TranslateInit(route *gin.Engine, cfg *ini.File) {
-    enabled := cfg.Section("google").Key("enable").MustBool()
-    if enabled == false {
-        fmt.Println("Dict: Google Disabled")
-        return
-    }
-    fmt.Println("Dict: Google Enabled")
-    appSecret = cfg.Section("google").Key("app_secret").String()
-    route.GET("/google", func(c *gin.Context){
-        text := c.Query("gdword")
-        result := Translate("zh-CN", text)
-        if result != "" {
-            c.HTML(http.StatusOK, "goldendict.tmpl", gin.H{
-                "Text": result,
-            })
-        } else {
-            c.String(http.StatusNotFound, "No Translation")
-        }
-    })
+func init() {
+	translator.Register(translator.Provider{
+		Name:  "google",
+		Title: "Google",
+		New:   New,
+	})
 }
 
-func Translate(targetLang string, text string) string {
-    ctx := context.Background()
-
-    lang, _ := language.Parse(targetLang)
-    opts := option.WithAPIKey(appSecret)
-    client, err := translate.NewClient(ctx, opts)
-    if err != nil {
-        fmt.Printf("xx%v", err)
-        return ""
-    }
-    defer client.Close()
-
-    resp, err := client.Translate(ctx, []string{text}, lang, nil)
-    if err != nil  || len(resp) == 0 {
-        fmt.Printf("sss%v", err)
-        return ""
-    }
-    return resp[0].Text
+// dialect maps canonical codes to the BCP-47 codes Google expects.
+var dialect = translator.Dialect{
+	Codes: map[string]string{
+		"zh": "zh-CN",
+	},
+}
+
+// Translator uses the Google Cloud Translation API with one client shared
+// by all requests.
+type Translator struct {
+	client *translate.Client
+}
+
+// New creates the Cloud API translator, or the keyless web translator when
+// mode is "web".
+func New(cfg *ini.Section) (translator.Translator, error) {
+	if cfg.Key("mode").In("cloud", []string{"cloud", "web"}) == "web" {
+		return newWebTranslator(), nil
+	}
+	appSecret := cfg.Key("app_secret").String()
+	client, err := translate.NewClient(context.Background(), option.WithAPIKey(appSecret))
+	if err != nil {
+		return nil, err
+	}
+	return &Translator{client: client}, nil
+}
+
+func (t *Translator) Translate(ctx context.Context, req translator.Request) (translator.Result, error) {
+	results, err := t.TranslateBatch(ctx, req, []string{req.Text})
+	if err != nil {
+		return translator.Result{}, err
+	}
+	return results[0], nil
+}
+
+// TranslateBatch translates all texts with a single API call.
+func (t *Translator) TranslateBatch(ctx context.Context, req translator.Request, texts []string) ([]translator.Result, error) {
+	target, err := language.Parse(dialect.Code(req.TargetLang))
+	if err != nil {
+		return nil, &translator.Error{Code: http.StatusBadRequest, Message: fmt.Sprintf("Invalid target language %q", req.TargetLang)}
+	}
+	opts := &translate.Options{
+		Format: translate.Text,
+	}
+	if req.Format == "html" {
+		opts.Format = translate.HTML
+	}
+	if req.SourceLang != "" {
+		opts.Source, err = language.Parse(dialect.Code(req.SourceLang))
+		if err != nil {
+			return nil, &translator.Error{Code: http.StatusBadRequest, Message: fmt.Sprintf("Invalid source language %q", req.SourceLang)}
+		}
+	}
+
+	resp, err := t.client.Translate(ctx, texts, target, opts)
+	if err != nil {
+		return nil, translator.Errorf("Google API request failed: %v", err)
+	}
+	if len(resp) != len(texts) {
+		return nil, translator.Errorf("Translation failed, API returns an incomplete result.")
+	}
+
+	results := make([]translator.Result, len(resp))
+	for i, translation := range resp {
+		results[i] = translator.Result{
+			Text:       translation.Text,
+			SourceLang: req.SourceLang,
+			TargetLang: req.TargetLang,
+		}
+		if req.SourceLang == "" {
+			results[i].SourceLang = dialect.Canonical(translation.Source.String())
+		}
+	}
+	return results, nil
+}
+
+// Detect uses the Google Detect API.
+func (t *Translator) Detect(ctx context.Context, text string) (string, float64, error) {
+	resp, err := t.client.DetectLanguage(ctx, []string{text})
+	if err != nil {
+		return "", 0, translator.Errorf("Google API request failed: %v", err)
+	}
+	if len(resp) == 0 || len(resp[0]) == 0 {
+		return "", 0, translator.ErrNoTranslation
+	}
+	detection := resp[0][0]
+	return dialect.Canonical(detection.Language.String()), detection.Confidence, nil
 }
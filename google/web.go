@@ -0,0 +1,152 @@
+package google
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/tidwall/gjson"
+	"github.com/yangxin0/gd-website-api/translator"
+)
+
+const webURL = "https://translate.googleapis.com/translate_a/single"
+
+// WebTranslator uses the keyless web translate endpoint, which answers with
+// nested arrays instead of JSON objects:
+//
+//	[0] sentences: [translation, original, ...], the last one holding the
+//	    transliterations [null, null, target, source]
+//	[1] dictionary (dt=bd): [part of speech, [terms], [[term, [back
+//	    translations], null, score], ...], base form, ...]
+//	[2] detected source language
+//	[6] detection confidence
+type WebTranslator struct {
+	client *http.Client
+}
+
+func newWebTranslator() *WebTranslator {
+	return &WebTranslator{
+		client: &http.Client{Timeout: 10 * time.Second},
+	}
+}
+
+func (t *WebTranslator) query(ctx context.Context, sourceLang string, targetLang string, text string) (gjson.Result, error) {
+	if sourceLang == "" {
+		sourceLang = "auto"
+	}
+	if targetLang == "" {
+		targetLang = "en"
+	}
+	params := url.Values{
+		"client": {"gtx"},
+		"sl":     {sourceLang},
+		"tl":     {targetLang},
+		"hl":     {targetLang},
+		"dt":     {"t", "bd", "rm"},
+		"ie":     {"UTF-8"},
+		"oe":     {"UTF-8"},
+		"q":      {text},
+	}
+	request, err := http.NewRequestWithContext(ctx, "GET", webURL+"?"+params.Encode(), nil)
+	if err != nil {
+		return gjson.Result{}, err
+	}
+	request.Header.Set("User-Agent", "Mozilla/5.0")
+
+	resp, err := t.client.Do(request)
+	if err != nil {
+		return gjson.Result{}, translator.Errorf("Google web request failed: %v", err)
+	}
+	defer resp.Body.Close()
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return gjson.Result{}, translator.Errorf("Google web request failed: %v", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return gjson.Result{}, &translator.Error{Code: resp.StatusCode, Message: "Google web request failed: " + resp.Status}
+	}
+	if !gjson.ValidBytes(body) {
+		return gjson.Result{}, translator.Errorf("Google web returns an invalid result")
+	}
+	return gjson.ParseBytes(body), nil
+}
+
+func (t *WebTranslator) Translate(ctx context.Context, req translator.Request) (translator.Result, error) {
+	res, err := t.query(ctx, dialect.Code(req.SourceLang), dialect.Code(req.TargetLang), req.Text)
+	if err != nil {
+		return translator.Result{}, err
+	}
+
+	var text strings.Builder
+	var targetTranslit, sourceTranslit string
+	res.Get("0").ForEach(func(_, sentence gjson.Result) bool {
+		if sentence.Get("0").Type == gjson.String {
+			text.WriteString(sentence.Get("0").String())
+		} else {
+			targetTranslit = sentence.Get("2").String()
+			sourceTranslit = sentence.Get("3").String()
+		}
+		return true
+	})
+
+	result := translator.Result{
+		Text:       text.String(),
+		SourceLang: req.SourceLang,
+		TargetLang: req.TargetLang,
+	}
+	if result.SourceLang == "" {
+		result.SourceLang = dialect.Canonical(res.Get("2").String())
+	}
+	if translator.IsWord(req.Text) {
+		result.Entry = webEntry(req, res.Get("1"), sourceTranslit, targetTranslit)
+	}
+	return result, nil
+}
+
+// webEntry builds a dictionary entry from the dt=bd data and the
+// transliterations; it returns nil when there is neither.
+func webEntry(req translator.Request, dict gjson.Result, sourceTranslit string, targetTranslit string) *translator.Entry {
+	entry := &translator.Entry{Word: req.Text}
+	if sourceTranslit != "" {
+		entry.Phonetics = append(entry.Phonetics, translator.Phonetic{IPA: sourceTranslit})
+	}
+	if targetTranslit != "" {
+		entry.Phonetics = append(entry.Phonetics, translator.Phonetic{Label: req.TargetLang, IPA: targetTranslit})
+	}
+	dict.ForEach(func(_, group gjson.Result) bool {
+		if lemma := group.Get("3").String(); lemma != "" {
+			entry.Lemma = lemma
+		}
+		pos := group.Get("0").String()
+		group.Get("2").ForEach(func(_, term gjson.Result) bool {
+			var back []string
+			term.Get("1").ForEach(func(_, word gjson.Result) bool {
+				back = append(back, word.String())
+				return true
+			})
+			entry.Senses = append(entry.Senses, translator.Sense{
+				PartOfSpeech: pos,
+				Meaning:      strings.Join(back, ", "),
+				Translations: []string{term.Get("0").String()},
+			})
+			return true
+		})
+		return true
+	})
+	if len(entry.Phonetics) == 0 && len(entry.Senses) == 0 {
+		return nil
+	}
+	return entry
+}
+
+// Detect returns the language the web endpoint detected for text.
+func (t *WebTranslator) Detect(ctx context.Context, text string) (string, float64, error) {
+	res, err := t.query(ctx, "", "en", text)
+	if err != nil {
+		return "", 0, err
+	}
+	return dialect.Canonical(res.Get("2").String()), res.Get("6").Float(), nil
+}
@@ -0,0 +1,228 @@
+package libretranslate
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/yangxin0/gd-website-api/translator"
+	"gopkg.in/ini.v1"
+)
+
+func init() {
+	translator.Register(translator.Provider{
+		Name:  "libretranslate",
+		Title: "LibreTranslate",
+		New:   New,
+	})
+}
+
+// dialect maps canonical codes to LibreTranslate codes, which use "zt" for
+// Traditional Chinese and no region subtags.
+var dialect = translator.Dialect{
+	Codes: map[string]string{
+		"zh":    "zh",
+		"zh-TW": "zt",
+	},
+	Default: func(lang string) string {
+		return strings.SplitN(lang, "-", 2)[0]
+	},
+}
+
+// Translation is the response of /translate.
+type Translation struct {
+	TranslatedText   string   `json:"translatedText"`
+	Alternatives     []string `json:"alternatives"`
+	DetectedLanguage *struct {
+		Confidence float64 `json:"confidence"`
+		Language   string  `json:"language"`
+	} `json:"detectedLanguage"`
+}
+
+// BatchTranslation is the response of /translate when q is a list.
+type BatchTranslation struct {
+	TranslatedText   []string `json:"translatedText"`
+	DetectedLanguage []struct {
+		Confidence float64 `json:"confidence"`
+		Language   string  `json:"language"`
+	} `json:"detectedLanguage"`
+}
+
+// Detection is one element of the /detect response. Confidence is a
+// percentage.
+type Detection struct {
+	Confidence float64 `json:"confidence"`
+	Language   string  `json:"language"`
+}
+
+// Language is one element of the /languages response.
+type Language struct {
+	Code    string   `json:"code"`
+	Name    string   `json:"name"`
+	Targets []string `json:"targets"`
+}
+
+// Translator uses a LibreTranslate compatible server, which can run
+// without internet access.
+type Translator struct {
+	url          string
+	apiKey       string
+	alternatives int
+	client       *http.Client
+}
+
+func New(cfg *ini.Section) (translator.Translator, error) {
+	return &Translator{
+		url:          strings.TrimRight(cfg.Key("url").MustString("http://localhost:5000"), "/"),
+		apiKey:       cfg.Key("api_key").String(),
+		alternatives: cfg.Key("alternatives").MustInt(3),
+		client:       &http.Client{Timeout: time.Duration(cfg.Key("timeout").MustInt(30)) * time.Second},
+	}, nil
+}
+
+// Routes serves the languages of the server under /libretranslate/languages.
+func (t *Translator) Routes(group *gin.RouterGroup) {
+	group.GET("/languages", func(c *gin.Context) {
+		languages, err := t.Languages(c.Request.Context())
+		if err != nil {
+			c.String(translator.StatusCode(err), err.Error())
+			return
+		}
+		c.JSON(http.StatusOK, languages)
+	})
+}
+
+// do sends a request to path and decodes the JSON answer into v. body is
+// sent as JSON together with the API key when it is not nil.
+func (t *Translator) do(ctx context.Context, method string, path string, body map[string]any, v any) error {
+	var reader io.Reader
+	if body != nil {
+		if t.apiKey != "" {
+			body["api_key"] = t.apiKey
+		}
+		data, err := json.Marshal(body)
+		if err != nil {
+			return err
+		}
+		reader = bytes.NewReader(data)
+	}
+	request, err := http.NewRequestWithContext(ctx, method, t.url+path, reader)
+	if err != nil {
+		return err
+	}
+	if body != nil {
+		request.Header.Set("Content-Type", "application/json")
+	}
+
+	resp, err := t.client.Do(request)
+	if err != nil {
+		return translator.Errorf("LibreTranslate request failed: %v", err)
+	}
+	defer resp.Body.Close()
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return translator.Errorf("LibreTranslate request failed: %v", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		var apiErr struct {
+			Error string `json:"error"`
+		}
+		if json.Unmarshal(data, &apiErr) != nil || apiErr.Error == "" {
+			apiErr.Error = string(data)
+		}
+		return &translator.Error{Code: resp.StatusCode, Message: "LibreTranslate error: " + apiErr.Error}
+	}
+	if err := json.Unmarshal(data, v); err != nil {
+		return translator.Errorf("LibreTranslate returned invalid JSON: %v", err)
+	}
+	return nil
+}
+
+// params returns the /translate parameters shared by single and batch
+// translations.
+func params(req translator.Request, q any) map[string]any {
+	source := dialect.Code(req.SourceLang)
+	if source == "" {
+		source = "auto"
+	}
+	format := "text"
+	if req.Format == "html" {
+		format = "html"
+	}
+	return map[string]any{
+		"q":      q,
+		"source": source,
+		"target": dialect.Code(req.TargetLang),
+		"format": format,
+	}
+}
+
+func (t *Translator) Translate(ctx context.Context, req translator.Request) (translator.Result, error) {
+	body := params(req, req.Text)
+	if t.alternatives > 0 {
+		body["alternatives"] = t.alternatives
+	}
+	var result Translation
+	if err := t.do(ctx, "POST", "/translate", body, &result); err != nil {
+		return translator.Result{}, err
+	}
+	sourceLang := req.SourceLang
+	if result.DetectedLanguage != nil {
+		sourceLang = dialect.Canonical(result.DetectedLanguage.Language)
+	}
+	return translator.Result{
+		Text:         result.TranslatedText,
+		Alternatives: result.Alternatives,
+		SourceLang:   sourceLang,
+		TargetLang:   req.TargetLang,
+	}, nil
+}
+
+// TranslateBatch translates all texts with a single /translate call.
+func (t *Translator) TranslateBatch(ctx context.Context, req translator.Request, texts []string) ([]translator.Result, error) {
+	var result BatchTranslation
+	if err := t.do(ctx, "POST", "/translate", params(req, texts), &result); err != nil {
+		return nil, err
+	}
+	if len(result.TranslatedText) != len(texts) {
+		return nil, translator.Errorf("LibreTranslate returned a wrong number of translations")
+	}
+	results := make([]translator.Result, len(texts))
+	for i, text := range result.TranslatedText {
+		sourceLang := req.SourceLang
+		if i < len(result.DetectedLanguage) {
+			sourceLang = dialect.Canonical(result.DetectedLanguage[i].Language)
+		}
+		results[i] = translator.Result{
+			Text:       text,
+			SourceLang: sourceLang,
+			TargetLang: req.TargetLang,
+		}
+	}
+	return results, nil
+}
+
+func (t *Translator) Detect(ctx context.Context, text string) (string, float64, error) {
+	var detections []Detection
+	if err := t.do(ctx, "POST", "/detect", map[string]any{"q": text}, &detections); err != nil {
+		return "", 0, err
+	}
+	if len(detections) == 0 {
+		return "", 0, translator.ErrNoTranslation
+	}
+	return dialect.Canonical(detections[0].Language), detections[0].Confidence / 100, nil
+}
+
+// Languages lists the languages supported by the server.
+func (t *Translator) Languages(ctx context.Context) ([]Language, error) {
+	var languages []Language
+	if err := t.do(ctx, "GET", "/languages", nil, &languages); err != nil {
+		return nil, err
+	}
+	return languages, nil
+}
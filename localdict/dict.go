@@ -0,0 +1,171 @@
+package localdict
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+)
+
+// Record is one dictionary entry. Definition and Translation hold one sense
+// per line.
+type Record struct {
+	Word        string
+	Phonetic    string
+	Definition  string
+	Translation string
+	// Exchange lists the inflections in ECDICT notation, e.g.
+	// "p:went/d:gone/i:going/3:goes/0:go".
+	Exchange string
+}
+
+// Dict is an in-memory index of the records of a dictionary file, keyed by
+// lower case headword, with a second index from inflected forms and
+// synonyms to their headword.
+type Dict struct {
+	Name    string
+	records map[string]*Record
+	lemmas  map[string]string
+}
+
+func newDict(name string) *Dict {
+	return &Dict{
+		Name:    name,
+		records: make(map[string]*Record),
+		lemmas:  make(map[string]string),
+	}
+}
+
+// Open loads a dictionary file. format is "ecdict" or "stardict"; when it
+// is empty the format follows from the extension, .csv or .ifo.
+func Open(path string, format string) (*Dict, error) {
+	if format == "" {
+		switch strings.ToLower(filepath.Ext(path)) {
+		case ".csv":
+			format = "ecdict"
+		case ".ifo":
+			format = "stardict"
+		}
+	}
+	switch format {
+	case "ecdict":
+		return openECDICT(path)
+	case "stardict":
+		return openStarDict(path)
+	default:
+		return nil, fmt.Errorf("unknown dictionary format %q for %s", format, path)
+	}
+}
+
+// Len returns the number of headwords.
+func (d *Dict) Len() int {
+	return len(d.records)
+}
+
+// add indexes a record, keeping the first one of duplicate headwords, and
+// indexes the inflections of its exchange field.
+func (d *Dict) add(r *Record) {
+	key := strings.ToLower(r.Word)
+	if key == "" {
+		return
+	}
+	if _, ok := d.records[key]; ok {
+		return
+	}
+	d.records[key] = r
+	for _, ex := range exchange(r.Exchange) {
+		switch ex.kind {
+		case "0":
+			d.addLemma(key, ex.value)
+		case "1":
+			// names the inflections the word itself is, not a word
+		default:
+			d.addLemma(ex.value, r.Word)
+		}
+	}
+}
+
+// addLemma points form at the headword lemma unless form already has one.
+func (d *Dict) addLemma(form string, lemma string) {
+	form = strings.ToLower(form)
+	if form == "" || form == strings.ToLower(lemma) {
+		return
+	}
+	if _, ok := d.lemmas[form]; !ok {
+		d.lemmas[form] = lemma
+	}
+}
+
+// Lookup returns the record of word. When word is not a headword, it
+// returns the record of its lemma, found through the inflection and synonym
+// index or, failing that, by stripping regular English suffixes.
+func (d *Dict) Lookup(word string) (*Record, bool) {
+	key := strings.ToLower(strings.TrimSpace(word))
+	if r, ok := d.records[key]; ok {
+		return r, true
+	}
+	if lemma, ok := d.lemmas[key]; ok {
+		if r, ok := d.records[strings.ToLower(lemma)]; ok {
+			return r, true
+		}
+	}
+	for _, lemma := range candidates(key) {
+		if r, ok := d.records[lemma]; ok {
+			return r, true
+		}
+	}
+	return nil, false
+}
+
+// suffixes are the regular English inflections tried by candidates, longest
+// first.
+var suffixes = []struct{ suffix, replace string }{
+	{"ies", "y"},
+	{"ied", "y"},
+	{"ier", "y"},
+	{"iest", "y"},
+	{"ves", "f"},
+	{"ing", ""},
+	{"ing", "e"},
+	{"est", ""},
+	{"est", "e"},
+	{"es", ""},
+	{"ed", ""},
+	{"ed", "e"},
+	{"er", ""},
+	{"er", "e"},
+	{"s", ""},
+}
+
+// candidates returns the possible lemmas of an inflected English word,
+// including the undoubled consonant of forms like "running".
+func candidates(word string) []string {
+	var lemmas []string
+	for _, s := range suffixes {
+		stem, ok := strings.CutSuffix(word, s.suffix)
+		if !ok || len(stem) < 2 {
+			continue
+		}
+		lemmas = append(lemmas, stem+s.replace)
+		if s.replace == "" && len(stem) > 2 && stem[len(stem)-1] == stem[len(stem)-2] {
+			lemmas = append(lemmas, stem[:len(stem)-1])
+		}
+	}
+	return lemmas
+}
+
+type inflection struct {
+	kind  string
+	value string
+}
+
+// exchange splits an ECDICT exchange field into its inflections.
+func exchange(field string) []inflection {
+	var inflections []inflection
+	for _, item := range strings.Split(field, "/") {
+		kind, value, ok := strings.Cut(item, ":")
+		if ok && value != "" {
+			inflections = append(inflections, inflection{kind: kind, value: value})
+		}
+	}
+	return inflections
+}
@@ -0,0 +1,148 @@
+package localdict
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+const ecdict = "\ufeffword,phonetic,definition,translation,pos,collins,oxford,tag,bnc,frq,exchange,detail,audio\n" +
+	`run,rʌn,"v. move fast\nn. a trip","v. 跑\nn. 奔跑",,,,,,,p:ran/d:run/i:running/3:runs,,` + "\n" +
+	`ran,ræn,,v. run的过去式,,,,,,,0:run/1:p,,` + "\n" +
+	`mice,maɪs,,n. 老鼠,,,,,,,0:mouse/1:s,,` + "\n" +
+	`mouse,maʊs,,n. 老鼠,,,,,,,s:mice,,` + "\n" +
+	`Run,,,duplicate,,,,,,,,,` + "\n" +
+	`quiet,,"adj. making little noise",,,,,,,,,,` + "\n"
+
+func openTestECDICT(t *testing.T) *Dict {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "ecdict.csv")
+	if err := os.WriteFile(path, []byte(ecdict), 0644); err != nil {
+		t.Fatal(err)
+	}
+	d, err := Open(path, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	return d
+}
+
+func TestOpenECDICT(t *testing.T) {
+	d := openTestECDICT(t)
+	if d.Len() != 5 {
+		t.Errorf("Len() = %d, want 5", d.Len())
+	}
+
+	tests := []struct {
+		word     string
+		headword string
+	}{
+		{"run", "run"},
+		{"RUN", "run"},
+		{"ran", "ran"},
+		{"running", "run"},
+		{"runs", "run"},
+		{"mice", "mice"},
+		{"quieter", "quiet"},
+		{"quietest", "quiet"},
+	}
+	for _, tt := range tests {
+		r, ok := d.Lookup(tt.word)
+		if !ok {
+			t.Errorf("Lookup(%q) not found", tt.word)
+			continue
+		}
+		if r.Word != tt.headword {
+			t.Errorf("Lookup(%q) = %q, want %q", tt.word, r.Word, tt.headword)
+		}
+	}
+
+	r, _ := d.Lookup("run")
+	if want := "v. 跑\nn. 奔跑"; r.Translation != want {
+		t.Errorf("Translation = %q, want %q", r.Translation, want)
+	}
+	if want := "v. move fast\nn. a trip"; r.Definition != want {
+		t.Errorf("Definition = %q, want %q", r.Definition, want)
+	}
+}
+
+func TestExchangeIndex(t *testing.T) {
+	d := openTestECDICT(t)
+	want := map[string]string{
+		"ran":     "run",
+		"running": "run",
+		"runs":    "run",
+		"mice":    "mouse",
+	}
+	for form, lemma := range want {
+		if got := d.lemmas[form]; got != lemma {
+			t.Errorf("lemmas[%q] = %q, want %q", form, got, lemma)
+		}
+	}
+	// "d:run" is the headword itself and "1:p" names an inflection kind.
+	for _, form := range []string{"run", "p", "s"} {
+		if lemma, ok := d.lemmas[form]; ok {
+			t.Errorf("lemmas[%q] = %q, want none", form, lemma)
+		}
+	}
+}
+
+func TestCandidates(t *testing.T) {
+	tests := []struct {
+		word string
+		want []string
+	}{
+		{"studies", []string{"study", "studi", "studie"}},
+		{"running", []string{"runn", "run", "runne"}},
+		{"makes", []string{"mak", "make"}},
+		{"is", nil},
+	}
+	for _, tt := range tests {
+		got := candidates(tt.word)
+		for _, lemma := range tt.want {
+			if !contains(got, lemma) {
+				t.Errorf("candidates(%q) = %q, missing %q", tt.word, got, lemma)
+			}
+		}
+		if tt.want == nil && got != nil {
+			t.Errorf("candidates(%q) = %q, want none", tt.word, got)
+		}
+	}
+}
+
+func contains(list []string, s string) bool {
+	for _, item := range list {
+		if item == s {
+			return true
+		}
+	}
+	return false
+}
+
+func TestToEntry(t *testing.T) {
+	d := openTestECDICT(t)
+	r, _ := d.Lookup("running")
+	entry := toEntry("running", r)
+	if entry.Word != "running" || entry.Lemma != "run" {
+		t.Errorf("Word, Lemma = %q, %q", entry.Word, entry.Lemma)
+	}
+	senses := []string{"跑", "奔跑"}
+	for i, s := range entry.Senses {
+		if i >= len(senses) || s.Meaning != senses[i] {
+			t.Errorf("Senses = %+v", entry.Senses)
+			break
+		}
+	}
+	if len(entry.Forms) != 4 || entry.Forms[0].Name != "past tense" || entry.Forms[0].Value != "ran" {
+		t.Errorf("Forms = %+v", entry.Forms)
+	}
+
+	r, _ = d.Lookup("quiet")
+	entry = toEntry("quiet", r)
+	if want := "making little noise"; len(entry.Senses) != 1 || entry.Senses[0].Meaning != want {
+		t.Errorf("Senses from definition = %+v", entry.Senses)
+	}
+	if entry.Forms != nil {
+		t.Errorf("Forms = %+v", entry.Forms)
+	}
+}
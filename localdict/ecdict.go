@@ -0,0 +1,62 @@
+package localdict
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// openECDICT loads an ECDICT CSV file. The columns are named by the header
+// row; word, phonetic, definition, translation and exchange are used. Line
+// breaks inside fields are stored as the two characters `\n`.
+func openECDICT(path string) (*Dict, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	r := csv.NewReader(f)
+	r.LazyQuotes = true
+	r.FieldsPerRecord = -1
+	header, err := r.Read()
+	if err != nil {
+		return nil, fmt.Errorf("%s: %v", path, err)
+	}
+	columns := make(map[string]int)
+	for i, name := range header {
+		columns[strings.TrimSpace(strings.TrimPrefix(name, "\ufeff"))] = i
+	}
+	if _, ok := columns["word"]; !ok {
+		return nil, fmt.Errorf("%s: no word column", path)
+	}
+	field := func(row []string, name string) string {
+		i, ok := columns[name]
+		if !ok || i >= len(row) {
+			return ""
+		}
+		return strings.ReplaceAll(row[i], `\n`, "\n")
+	}
+
+	d := newDict(strings.TrimSuffix(filepath.Base(path), filepath.Ext(path)))
+	for {
+		row, err := r.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("%s: %v", path, err)
+		}
+		d.add(&Record{
+			Word:        field(row, "word"),
+			Phonetic:    field(row, "phonetic"),
+			Definition:  field(row, "definition"),
+			Translation: field(row, "translation"),
+			Exchange:    field(row, "exchange"),
+		})
+	}
+	return d, nil
+}
@@ -0,0 +1,214 @@
+package localdict
+
+import (
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"encoding/binary"
+	"fmt"
+	"html"
+	"io"
+	"os"
+	"regexp"
+	"strings"
+)
+
+// ifo holds the options of a StarDict .ifo file.
+type ifo struct {
+	bookName         string
+	sameTypeSequence string
+	offsetBits       int
+}
+
+// openStarDict loads a StarDict dictionary from its .ifo file. The .idx and
+// .dict files next to it may be gzip or dictzip compressed (.idx.gz,
+// .dict.dz); an optional .syn file adds synonyms to the lemma index.
+func openStarDict(path string) (*Dict, error) {
+	base := strings.TrimSuffix(path, ".ifo")
+	info, err := readIfo(path)
+	if err != nil {
+		return nil, err
+	}
+	index, err := readFile(base+".idx", base+".idx.gz")
+	if err != nil {
+		return nil, err
+	}
+	data, err := readFile(base+".dict", base+".dict.dz")
+	if err != nil {
+		return nil, err
+	}
+
+	d := newDict(info.bookName)
+	var words []string
+	for len(index) > 0 {
+		end := bytes.IndexByte(index, 0)
+		if end < 0 || len(index) < end+1+info.offsetBits/8+4 {
+			return nil, fmt.Errorf("%s.idx: truncated entry", base)
+		}
+		word := string(index[:end])
+		index = index[end+1:]
+		var offset uint64
+		if info.offsetBits == 64 {
+			offset = binary.BigEndian.Uint64(index)
+			index = index[8:]
+		} else {
+			offset = uint64(binary.BigEndian.Uint32(index))
+			index = index[4:]
+		}
+		size := uint64(binary.BigEndian.Uint32(index))
+		index = index[4:]
+		if offset+size > uint64(len(data)) {
+			return nil, fmt.Errorf("%s.dict: entry %q out of range", base, word)
+		}
+		words = append(words, word)
+		d.add(record(word, data[offset:offset+size], info.sameTypeSequence))
+	}
+
+	if syn, err := readFile(base + ".syn"); err == nil {
+		for len(syn) > 0 {
+			end := bytes.IndexByte(syn, 0)
+			if end < 0 || len(syn) < end+5 {
+				break
+			}
+			i := binary.BigEndian.Uint32(syn[end+1:])
+			if int(i) < len(words) {
+				d.addLemma(string(syn[:end]), words[i])
+			}
+			syn = syn[end+5:]
+		}
+	}
+	return d, nil
+}
+
+func readIfo(path string) (ifo, error) {
+	info := ifo{offsetBits: 32}
+	f, err := os.Open(path)
+	if err != nil {
+		return info, err
+	}
+	defer f.Close()
+	scanner := bufio.NewScanner(f)
+	if !scanner.Scan() || !strings.HasPrefix(scanner.Text(), "StarDict's dict ifo file") {
+		return info, fmt.Errorf("%s: not a StarDict .ifo file", path)
+	}
+	for scanner.Scan() {
+		key, value, _ := strings.Cut(scanner.Text(), "=")
+		switch strings.TrimSpace(key) {
+		case "bookname":
+			info.bookName = strings.TrimSpace(value)
+		case "sametypesequence":
+			info.sameTypeSequence = strings.TrimSpace(value)
+		case "idxoffsetbits":
+			if strings.TrimSpace(value) == "64" {
+				info.offsetBits = 64
+			}
+		}
+	}
+	return info, scanner.Err()
+}
+
+// readFile reads the first of paths that exists, decompressing it when its
+// name ends with .gz or .dz.
+func readFile(paths ...string) ([]byte, error) {
+	for _, path := range paths {
+		f, err := os.Open(path)
+		if os.IsNotExist(err) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		defer f.Close()
+		var r io.Reader = f
+		if strings.HasSuffix(path, ".gz") || strings.HasSuffix(path, ".dz") {
+			gz, err := gzip.NewReader(f)
+			if err != nil {
+				return nil, fmt.Errorf("%s: %v", path, err)
+			}
+			defer gz.Close()
+			r = gz
+		}
+		return io.ReadAll(r)
+	}
+	return nil, fmt.Errorf("%s: %w", paths[0], os.ErrNotExist)
+}
+
+// record decodes the data of one StarDict entry. Text fields become the
+// translation and the "t" field the phonetic; binary fields such as images
+// and sounds are skipped.
+func record(word string, data []byte, types string) *Record {
+	r := &Record{Word: word}
+	var lines []string
+	for _, f := range fields(data, types) {
+		switch f.kind {
+		case 't':
+			r.Phonetic = string(f.data)
+		case 'm', 'l', 'y', 'k':
+			lines = append(lines, string(f.data))
+		case 'g', 'h', 'x':
+			lines = append(lines, stripTags(string(f.data)))
+		}
+	}
+	r.Translation = strings.TrimSpace(strings.Join(lines, "\n"))
+	return r
+}
+
+type field struct {
+	kind byte
+	data []byte
+}
+
+// fields splits entry data into typed fields. With a sametypesequence the
+// types are implied and the last field runs to the end of the data;
+// without one each field starts with its type. Lower case types are NUL
+// terminated text and upper case types are prefixed with their size.
+func fields(data []byte, types string) []field {
+	var result []field
+	for i := 0; len(data) > 0; i++ {
+		var kind byte
+		if types != "" {
+			if i >= len(types) {
+				break
+			}
+			kind = types[i]
+		} else {
+			kind, data = data[0], data[1:]
+		}
+		last := types != "" && i == len(types)-1
+		var value []byte
+		switch {
+		case last:
+			value, data = data, nil
+		case kind >= 'a' && kind <= 'z':
+			end := bytes.IndexByte(data, 0)
+			if end < 0 {
+				value, data = data, nil
+			} else {
+				value, data = data[:end], data[end+1:]
+			}
+		default:
+			if len(data) < 4 {
+				return result
+			}
+			size := int(binary.BigEndian.Uint32(data))
+			data = data[4:]
+			if size > len(data) {
+				return result
+			}
+			value, data = data[:size], data[size:]
+		}
+		result = append(result, field{kind: kind, data: value})
+	}
+	return result
+}
+
+var (
+	lineBreak = regexp.MustCompile(`(?i)<br\s*/?>|</(p|div|li)>`)
+	tag       = regexp.MustCompile(`<[^>]*>`)
+)
+
+// stripTags converts HTML or XDXF markup to plain text lines.
+func stripTags(s string) string {
+	s = lineBreak.ReplaceAllString(s, "\n")
+	return html.UnescapeString(tag.ReplaceAllString(s, ""))
+}
@@ -0,0 +1,192 @@
+package localdict
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/binary"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+type starEntry struct {
+	word string
+	data []byte
+}
+
+// writeStarDict writes a dictionary named "test" to dir and returns the
+// path of its .ifo file. The .dict file is gzip compressed like a .dict.dz.
+func writeStarDict(t *testing.T, dir string, ifo string, offsetBits int, entries []starEntry, syn map[string]uint32) string {
+	t.Helper()
+	var idx, dict bytes.Buffer
+	for _, e := range entries {
+		idx.WriteString(e.word)
+		idx.WriteByte(0)
+		if offsetBits == 64 {
+			binary.Write(&idx, binary.BigEndian, uint64(dict.Len()))
+		} else {
+			binary.Write(&idx, binary.BigEndian, uint32(dict.Len()))
+		}
+		binary.Write(&idx, binary.BigEndian, uint32(len(e.data)))
+		dict.Write(e.data)
+	}
+	var dz bytes.Buffer
+	gz := gzip.NewWriter(&dz)
+	gz.Write(dict.Bytes())
+	gz.Close()
+
+	base := filepath.Join(dir, "test")
+	files := map[string][]byte{
+		".ifo":     []byte("StarDict's dict ifo file\nversion=2.4.2\nbookname=Test\n" + ifo),
+		".idx":     idx.Bytes(),
+		".dict.dz": dz.Bytes(),
+	}
+	if syn != nil {
+		var b bytes.Buffer
+		for word, i := range syn {
+			b.WriteString(word)
+			b.WriteByte(0)
+			binary.Write(&b, binary.BigEndian, i)
+		}
+		files[".syn"] = b.Bytes()
+	}
+	for ext, data := range files {
+		if err := os.WriteFile(base+ext, data, 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return base + ".ifo"
+}
+
+func TestOpenStarDict(t *testing.T) {
+	entries := []starEntry{
+		{"apple", []byte("ˈæpl\x00n. 苹果")},
+		{"go", []byte("ɡoʊ\x00v. 去\n走")},
+	}
+	for _, bits := range []int{32, 64} {
+		ifo := "sametypesequence=tm\n"
+		if bits == 64 {
+			ifo += "idxoffsetbits=64\n"
+		}
+		path := writeStarDict(t, t.TempDir(), ifo, bits, entries, map[string]uint32{"went": 1})
+		d, err := Open(path, "")
+		if err != nil {
+			t.Fatalf("%d bit offsets: %v", bits, err)
+		}
+		if d.Name != "Test" || d.Len() != 2 {
+			t.Errorf("%d bit offsets: name %q, %d words", bits, d.Name, d.Len())
+		}
+
+		tests := []struct {
+			word        string
+			headword    string
+			phonetic    string
+			translation string
+		}{
+			{"apple", "apple", "ˈæpl", "n. 苹果"},
+			{"Apple", "apple", "ˈæpl", "n. 苹果"},
+			{"went", "go", "ɡoʊ", "v. 去\n走"},
+			{"apples", "apple", "ˈæpl", "n. 苹果"},
+		}
+		for _, tt := range tests {
+			r, ok := d.Lookup(tt.word)
+			if !ok {
+				t.Errorf("%d bit offsets: Lookup(%q) not found", bits, tt.word)
+				continue
+			}
+			if r.Word != tt.headword || r.Phonetic != tt.phonetic || r.Translation != tt.translation {
+				t.Errorf("%d bit offsets: Lookup(%q) = %+v", bits, tt.word, r)
+			}
+		}
+		if _, ok := d.Lookup("pear"); ok {
+			t.Errorf("%d bit offsets: Lookup(pear) found", bits)
+		}
+	}
+}
+
+func TestOpenStarDictTruncated(t *testing.T) {
+	path := writeStarDict(t, t.TempDir(), "sametypesequence=m\n", 32, []starEntry{{"apple", []byte("苹果")}}, nil)
+	idx := filepath.Join(filepath.Dir(path), "test.idx")
+	data, _ := os.ReadFile(idx)
+	os.WriteFile(idx, data[:len(data)-2], 0644)
+	if _, err := Open(path, ""); err == nil {
+		t.Error("Open with a truncated .idx succeeded")
+	}
+
+	path = writeStarDict(t, t.TempDir(), "sametypesequence=m\n", 32, []starEntry{{"apple", []byte("苹果")}}, nil)
+	idx = filepath.Join(filepath.Dir(path), "test.idx")
+	data, _ = os.ReadFile(idx)
+	binary.BigEndian.PutUint32(data[len(data)-4:], 100)
+	os.WriteFile(idx, data, 0644)
+	if _, err := Open(path, ""); err == nil {
+		t.Error("Open with an entry past the end of .dict succeeded")
+	}
+}
+
+func TestFields(t *testing.T) {
+	sized := func(kind byte, data string) []byte {
+		b := []byte{kind}
+		b = binary.BigEndian.AppendUint32(b, uint32(len(data)))
+		return append(b, data...)
+	}
+
+	tests := []struct {
+		name  string
+		data  []byte
+		types string
+		want  []field
+	}{
+		{
+			name:  "single type runs to the end",
+			data:  []byte("苹果\x00not a terminator"),
+			types: "m",
+			want:  []field{{'m', []byte("苹果\x00not a terminator")}},
+		},
+		{
+			name:  "sametypesequence",
+			data:  []byte("ˈæpl\x00<b>apple</b>"),
+			types: "th",
+			want:  []field{{'t', []byte("ˈæpl")}, {'h', []byte("<b>apple</b>")}},
+		},
+		{
+			name:  "sametypesequence with a sized field",
+			data:  append(sized('W', "RIFF")[1:], "苹果"...),
+			types: "Wm",
+			want:  []field{{'W', []byte("RIFF")}, {'m', []byte("苹果")}},
+		},
+		{
+			name:  "typed fields",
+			data:  append([]byte("tˈæpl\x00m苹果\x00"), sized('P', "PNG")...),
+			types: "",
+			want:  []field{{'t', []byte("ˈæpl")}, {'m', []byte("苹果")}, {'P', []byte("PNG")}},
+		},
+		{
+			name:  "unterminated last typed field",
+			data:  []byte("m苹果"),
+			types: "",
+			want:  []field{{'m', []byte("苹果")}},
+		},
+		{
+			name:  "sized field longer than the data",
+			data:  []byte("m苹果\x00W\x00\x00\x00\x10RIFF"),
+			types: "",
+			want:  []field{{'m', []byte("苹果")}},
+		},
+	}
+	for _, tt := range tests {
+		if got := fields(tt.data, tt.types); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("%s: fields() = %q, want %q", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestRecord(t *testing.T) {
+	r := record("apple", []byte("ˈæpl\x00<b>n.</b> 苹果<br>苹果树&amp;果实"), "th")
+	if r.Phonetic != "ˈæpl" {
+		t.Errorf("Phonetic = %q", r.Phonetic)
+	}
+	if want := "n. 苹果\n苹果树&果实"; r.Translation != want {
+		t.Errorf("Translation = %q, want %q", r.Translation, want)
+	}
+}
@@ -0,0 +1,104 @@
+package localdict
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/yangxin0/gd-website-api/translator"
+	"gopkg.in/ini.v1"
+)
+
+func init() {
+	translator.Register(translator.Provider{
+		Name:  "local",
+		Title: "Local Dictionary",
+		New:   New,
+	})
+}
+
+// forms names the inflections of the ECDICT exchange field.
+var forms = map[string]string{
+	"p": "past tense",
+	"d": "past participle",
+	"i": "present participle",
+	"3": "third person singular",
+	"r": "comparative",
+	"t": "superlative",
+	"s": "plural",
+}
+
+// Translator looks words up in dictionary files loaded at startup, so it
+// works without internet access. The languages of the request are ignored;
+// they are fixed by the dictionaries.
+type Translator struct {
+	dicts []*Dict
+}
+
+func New(cfg *ini.Section) (translator.Translator, error) {
+	paths := cfg.Key("path").Strings(",")
+	if len(paths) == 0 {
+		return nil, fmt.Errorf("path is required")
+	}
+	t := &Translator{}
+	for _, path := range paths {
+		d, err := Open(path, cfg.Key("format").String())
+		if err != nil {
+			return nil, err
+		}
+		fmt.Printf("Local Dictionary: %s loaded %d words\n", d.Name, d.Len())
+		t.dicts = append(t.dicts, d)
+	}
+	return t, nil
+}
+
+// Translate returns the entry of the first dictionary that has the word or
+// its lemma.
+func (t *Translator) Translate(ctx context.Context, req translator.Request) (translator.Result, error) {
+	word := strings.TrimSpace(req.Text)
+	for _, d := range t.dicts {
+		r, ok := d.Lookup(word)
+		if !ok {
+			continue
+		}
+		entry := toEntry(word, r)
+		var text string
+		if len(entry.Senses) > 0 {
+			text = entry.Senses[0].Meaning
+		}
+		return translator.Result{
+			Text:       text,
+			SourceLang: req.SourceLang,
+			TargetLang: req.TargetLang,
+			Entry:      entry,
+		}, nil
+	}
+	return translator.Result{}, translator.ErrNoTranslation
+}
+
+// toEntry converts a record into a dictionary entry for word. The senses
+// come from the translation, or from the definition when there is none.
+func toEntry(word string, r *Record) *translator.Entry {
+	entry := &translator.Entry{Word: word}
+	if !strings.EqualFold(word, r.Word) {
+		entry.Lemma = r.Word
+	}
+	if r.Phonetic != "" {
+		entry.Phonetics = append(entry.Phonetics, translator.Phonetic{IPA: r.Phonetic})
+	}
+	senses := r.Translation
+	if senses == "" {
+		senses = r.Definition
+	}
+	for _, line := range strings.Split(senses, "\n") {
+		if line = strings.TrimSpace(line); line != "" {
+			entry.Senses = append(entry.Senses, translator.ParseSense(line))
+		}
+	}
+	for _, ex := range exchange(r.Exchange) {
+		if name, ok := forms[ex.kind]; ok {
+			entry.Forms = append(entry.Forms, translator.Form{Name: name, Value: ex.value})
+		}
+	}
+	return entry
+}
@@ -8,10 +8,7 @@ import (
 
 	"github.com/gin-contrib/cors"
 	"github.com/gin-gonic/gin"
-	"github.com/yangxin0/gd-website-api/deepl"
-	"github.com/yangxin0/gd-website-api/google"
-	"github.com/yangxin0/gd-website-api/openai"
-	"github.com/yangxin0/gd-website-api/youdao"
+	"github.com/yangxin0/gd-website-api/translator"
 	"gopkg.in/ini.v1"
 )
 
@@ -50,10 +47,7 @@ func main() {
     r.LoadHTMLGlob("templates/*")
 	r.Use(cors.Default())
 
-    deepl.TranslateInit(r, cfg)
-    youdao.TranslateInit(r, cfg)
-    google.TranslateInit(r, cfg)
-    openai.TranslateInit(r, cfg)
+    translator.Setup(r, cfg)
 
     // Catch-all route to handle undefined paths
 	r.NoRoute(func(c *gin.Context) {
@@ -0,0 +1,177 @@
+package openai
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	oai "github.com/sashabaranov/go-openai"
+	"github.com/sashabaranov/go-openai/jsonschema"
+	"github.com/yangxin0/gd-website-api/translator"
+)
+
+// DictEntry is the dictionary entry the model returns for a single word.
+type DictEntry struct {
+	Lemma    string      `json:"lemma"`
+	IPA      string      `json:"ipa"`
+	Senses   []DictSense `json:"senses"`
+	Synonyms []string    `json:"synonyms"`
+}
+
+// DictSense is one meaning of the word with its translations.
+type DictSense struct {
+	PartOfSpeech string        `json:"part_of_speech"`
+	Definition   string        `json:"definition"`
+	Translations []string      `json:"translations"`
+	Examples     []DictExample `json:"examples"`
+}
+
+// DictExample is an example sentence and its translation.
+type DictExample struct {
+	Text        string `json:"text"`
+	Translation string `json:"translation"`
+}
+
+func strictObject(properties map[string]jsonschema.Definition) jsonschema.Definition {
+	required := make([]string, 0, len(properties))
+	for name := range properties {
+		required = append(required, name)
+	}
+	sort.Strings(required)
+	return jsonschema.Definition{
+		Type:                 jsonschema.Object,
+		Properties:           properties,
+		Required:             required,
+		AdditionalProperties: false,
+	}
+}
+
+func stringArray() jsonschema.Definition {
+	return jsonschema.Definition{Type: jsonschema.Array, Items: &jsonschema.Definition{Type: jsonschema.String}}
+}
+
+// dictSchema describes DictEntry in the strict form required by structured
+// outputs: every property is required and no others are allowed.
+var dictSchema = strictObject(map[string]jsonschema.Definition{
+	"lemma": {Type: jsonschema.String, Description: "Dictionary form of the word"},
+	"ipa":   {Type: jsonschema.String, Description: "IPA transcription without slashes"},
+	"senses": {
+		Type: jsonschema.Array,
+		Items: func() *jsonschema.Definition {
+			sense := strictObject(map[string]jsonschema.Definition{
+				"part_of_speech": {Type: jsonschema.String, Description: "Abbreviated part of speech, e.g. n., v., adj."},
+				"definition":     {Type: jsonschema.String, Description: "Short definition in the source language"},
+				"translations":   stringArray(),
+				"examples": {
+					Type: jsonschema.Array,
+					Items: func() *jsonschema.Definition {
+						example := strictObject(map[string]jsonschema.Definition{
+							"text":        {Type: jsonschema.String},
+							"translation": {Type: jsonschema.String},
+						})
+						return &example
+					}(),
+				},
+			})
+			return &sense
+		}(),
+	},
+	"synonyms": stringArray(),
+})
+
+// Validate checks the fields the dictionary template relies on.
+func (e *DictEntry) Validate() error {
+	if strings.TrimSpace(e.Lemma) == "" {
+		return fmt.Errorf("dictionary entry has no lemma")
+	}
+	if len(e.Senses) == 0 {
+		return fmt.Errorf("dictionary entry has no senses")
+	}
+	for i, sense := range e.Senses {
+		if len(sense.Translations) == 0 {
+			return fmt.Errorf("sense %d has no translations", i+1)
+		}
+	}
+	return nil
+}
+
+// toEntry converts the model output into the shared dictionary entry.
+func (e *DictEntry) toEntry(word string) *translator.Entry {
+	entry := &translator.Entry{
+		Word:     word,
+		Lemma:    e.Lemma,
+		Synonyms: e.Synonyms,
+	}
+	if e.IPA != "" {
+		entry.Phonetics = []translator.Phonetic{{IPA: strings.Trim(e.IPA, "/[]")}}
+	}
+	for _, s := range e.Senses {
+		sense := translator.Sense{
+			PartOfSpeech: s.PartOfSpeech,
+			Meaning:      s.Definition,
+			Translations: s.Translations,
+		}
+		for _, example := range s.Examples {
+			sense.Examples = append(sense.Examples, translator.Example{Text: example.Text, Translation: example.Translation})
+		}
+		entry.Senses = append(entry.Senses, sense)
+	}
+	return entry
+}
+
+// lookup asks the model for a structured dictionary entry of a single word.
+func (t *Translator) lookup(ctx context.Context, prompt Prompt) (*DictEntry, error) {
+	schema, err := json.Marshal(dictSchema)
+	if err != nil {
+		return nil, err
+	}
+	from := ""
+	if prompt.SourceLang != "" {
+		from = " " + prompt.SourceLang
+	}
+	request := oai.ChatCompletionRequest{
+		Model: t.model,
+		Messages: []oai.ChatCompletionMessage{
+			{
+				Role:    oai.ChatMessageRoleSystem,
+				Content: fmt.Sprintf("You are a bilingual dictionary that translates into %s. Answer with a single JSON object matching this JSON schema: %s", prompt.TargetLang, schema),
+			},
+			{
+				Role:    oai.ChatMessageRoleUser,
+				Content: fmt.Sprintf("Write the dictionary entry of the%s word %q, with definitions in the source language and translations and example translations in %s.", from, prompt.Text, prompt.TargetLang),
+			},
+		},
+	}
+	if t.dictFormat == "json_schema" {
+		request.ResponseFormat = &oai.ChatCompletionResponseFormat{
+			Type: oai.ChatCompletionResponseFormatTypeJSONSchema,
+			JSONSchema: &oai.ChatCompletionResponseFormatJSONSchema{
+				Name:   "dictionary_entry",
+				Schema: dictSchema,
+				Strict: true,
+			},
+		}
+	} else {
+		request.ResponseFormat = &oai.ChatCompletionResponseFormat{
+			Type: oai.ChatCompletionResponseFormatTypeJSONObject,
+		}
+	}
+
+	resp, err := t.client.CreateChatCompletion(ctx, request)
+	if err != nil {
+		return nil, fmt.Errorf("OpenAI Error: %v", err)
+	}
+	if len(resp.Choices) == 0 {
+		return nil, fmt.Errorf("OpenAI Error: no choices returned")
+	}
+	var entry DictEntry
+	if err := json.Unmarshal([]byte(resp.Choices[0].Message.Content), &entry); err != nil {
+		return nil, fmt.Errorf("invalid dictionary entry: %v", err)
+	}
+	if err := entry.Validate(); err != nil {
+		return nil, err
+	}
+	return &entry, nil
+}
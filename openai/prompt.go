@@ -0,0 +1,94 @@
+package openai
+
+import (
+	"bytes"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"text/template"
+
+	oai "github.com/sashabaranov/go-openai"
+	"github.com/yangxin0/gd-website-api/translator"
+)
+
+// defaultStyle is used when a request does not select a style.
+const defaultStyle = "technical"
+
+// defaultPrompt is the technical style used when prompts_dir has no
+// technical.tmpl.
+const defaultPrompt = `{{ define "system" }}You are a highly skilled translation engine with expertise in the technology sector. Your function is to translate texts accurately into the target {{ .TargetLang }}, maintaining the original format, technical terms, and abbreviations. Do not add any explanations or annotations to the translated text.{{ end }}
+{{ define "user" }}Translate the following source text{{ if .SourceLang }} from {{ .SourceLang }}{{ end }} to {{ .TargetLang }}, Output translation directly without any additional text.
+{{ if .Context }}Context: {{ .Context }}
+{{ end }}Source Text: {{ .Text }},
+Translated Text:{{ end }}`
+
+// Prompt holds the variables available to prompt templates. Languages are
+// English names such as "Simplified Chinese".
+type Prompt struct {
+	SourceLang string
+	TargetLang string
+	Text       string
+	Context    string
+}
+
+// loadPrompts parses every <style>.tmpl in dir. Each file defines a
+// "system" and a "user" template.
+func loadPrompts(dir string) (map[string]*template.Template, error) {
+	prompts := map[string]*template.Template{
+		defaultStyle: template.Must(template.New(defaultStyle).Parse(defaultPrompt)),
+	}
+	files, err := filepath.Glob(filepath.Join(dir, "*.tmpl"))
+	if err != nil {
+		return nil, err
+	}
+	for _, file := range files {
+		data, err := os.ReadFile(file)
+		if err != nil {
+			return nil, err
+		}
+		style := strings.TrimSuffix(filepath.Base(file), ".tmpl")
+		tmpl, err := template.New(style).Parse(string(data))
+		if err != nil {
+			return nil, err
+		}
+		if tmpl.Lookup("system") == nil || tmpl.Lookup("user") == nil {
+			return nil, fmt.Errorf("%s must define the system and user templates", file)
+		}
+		prompts[style] = tmpl
+	}
+	return prompts, nil
+}
+
+// messages renders the chat messages of style for prompt.
+func (t *Translator) messages(style string, prompt Prompt) ([]oai.ChatCompletionMessage, error) {
+	if style == "" {
+		style = t.style
+	}
+	tmpl, ok := t.prompts[style]
+	if !ok {
+		return nil, &translator.Error{Code: http.StatusBadRequest, Message: "Unknown style " + style}
+	}
+	if prompt.TargetLang == "" {
+		prompt.TargetLang = "English"
+	}
+
+	var system, user bytes.Buffer
+	if err := tmpl.ExecuteTemplate(&system, "system", prompt); err != nil {
+		return nil, err
+	}
+	if err := tmpl.ExecuteTemplate(&user, "user", prompt); err != nil {
+		return nil, err
+	}
+	return []oai.ChatCompletionMessage{
+		{
+			Role:    oai.ChatMessageRoleSystem,
+			Content: strings.TrimSpace(system.String()),
+		},
+		{
+			Role:    oai.ChatMessageRoleUser,
+			Content: strings.TrimSpace(user.String()),
+		},
+	}, nil
+}
@@ -0,0 +1,60 @@
+package openai
+
+import (
+	"context"
+	"errors"
+	"io"
+	"strings"
+
+	oai "github.com/sashabaranov/go-openai"
+	"github.com/yangxin0/gd-website-api/translator"
+)
+
+// TranslateStream emits the translation token by token as the model
+// generates it. It stops when ctx is cancelled.
+func (t *Translator) TranslateStream(ctx context.Context, req translator.Request, emit func(delta string) error) (translator.Result, error) {
+	result := translator.Result{
+		SourceLang: req.SourceLang,
+		TargetLang: req.TargetLang,
+	}
+	messages, err := t.messages(req.Style, Prompt{
+		SourceLang: dialect.Code(req.SourceLang),
+		TargetLang: dialect.Code(req.TargetLang),
+		Text:       req.Text,
+		Context:    req.Context,
+	})
+	if err != nil {
+		return result, err
+	}
+
+	stream, err := t.client.CreateChatCompletionStream(ctx, oai.ChatCompletionRequest{
+		Model:    t.model,
+		Messages: messages,
+		Stream:   true,
+	})
+	if err != nil {
+		return result, translator.Errorf("OpenAI Error: %v", err)
+	}
+	defer stream.Close()
+
+	var text strings.Builder
+	for {
+		resp, err := stream.Recv()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return result, translator.Errorf("OpenAI Error: %v", err)
+		}
+		if len(resp.Choices) == 0 || resp.Choices[0].Delta.Content == "" {
+			continue
+		}
+		delta := resp.Choices[0].Delta.Content
+		text.WriteString(delta)
+		if err := emit(delta); err != nil {
+			return result, err
+		}
+	}
+	result.Text = text.String()
+	return result, nil
+}
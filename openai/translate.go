@@ -1,65 +1,156 @@
 package openai
 
 import (
+	"context"
 	"fmt"
-    "net/http"
-    "context"
+	"log"
+	"net/http"
+	"strings"
+	"text/template"
+	"time"
 
-	"github.com/gin-gonic/gin"
-	"gopkg.in/ini.v1"
 	oai "github.com/sashabaranov/go-openai"
+	"github.com/yangxin0/gd-website-api/translator"
+	"golang.org/x/text/language"
+	"golang.org/x/text/language/display"
+	"gopkg.in/ini.v1"
 )
 
-var appSecret = ""
+func init() {
+	translator.Register(translator.Provider{
+		Name:  "openai",
+		Title: "OpenAI",
+		New:   New,
+	})
+}
+
+// dialect maps canonical codes to the English language names used in the
+// prompts.
+var dialect = translator.Dialect{
+	Codes: map[string]string{
+		"zh":    "Simplified Chinese",
+		"zh-TW": "Traditional Chinese",
+	},
+	Default: func(lang string) string {
+		tag, err := language.Parse(lang)
+		if err != nil {
+			return lang
+		}
+		return display.English.Tags().Name(tag)
+	},
+}
+
+// Translator uses the chat completion API of OpenAI or of any compatible
+// server such as Ollama, llama.cpp, vLLM or Azure OpenAI.
+type Translator struct {
+	client  *oai.Client
+	model   string
+	style   string
+	prompts map[string]*template.Template
+	// dictionary enables structured entries for single words, requested
+	// with dictFormat "json_schema" or "json_object".
+	dictionary bool
+	dictFormat string
+}
+
+func New(cfg *ini.Section) (translator.Translator, error) {
+	appSecret := cfg.Key("app_secret").String()
+	baseURL := cfg.Key("base_url").String()
+
+	var config oai.ClientConfig
+	switch apiType := cfg.Key("api_type").MustString("openai"); apiType {
+	case "openai":
+		config = oai.DefaultConfig(appSecret)
+		if baseURL != "" {
+			config.BaseURL = baseURL
+		}
+	case "azure":
+		if baseURL == "" {
+			return nil, fmt.Errorf("base_url is required for Azure OpenAI")
+		}
+		config = oai.DefaultAzureConfig(appSecret, baseURL)
+		if apiVersion := cfg.Key("api_version").String(); apiVersion != "" {
+			config.APIVersion = apiVersion
+		}
+	default:
+		return nil, fmt.Errorf("unknown api_type %q", apiType)
+	}
+	config.OrgID = cfg.Key("organization").String()
+	config.HTTPClient = &http.Client{
+		Timeout: time.Duration(cfg.Key("timeout").MustInt(60)) * time.Second,
+	}
+
+	prompts, err := loadPrompts(cfg.Key("prompts_dir").MustString("prompts"))
+	if err != nil {
+		return nil, err
+	}
+
+	return &Translator{
+		client:  oai.NewClientWithConfig(config),
+		model:   cfg.Key("model").MustString(oai.GPT4o),
+		style:   cfg.Key("style").MustString(defaultStyle),
+		prompts: prompts,
 
-func TranslateInit(route *gin.Engine, cfg *ini.File) {
-    enabled := cfg.Section("openai").Key("enable").MustBool()
-    if enabled == false {
-        fmt.Println("Dict: OpenAI Disabled")
-        return
-    }
-    fmt.Println("Dict: OpenAI Enabled")
-    appSecret = cfg.Section("openai").Key("app_secret").String()
+		dictionary: cfg.Key("dictionary").MustBool(),
+		dictFormat: cfg.Key("dictionary_format").In("json_schema", []string{"json_schema", "json_object"}),
+	}, nil
+}
 
-    route.GET("/openai", func(c *gin.Context){
-        text := c.Query("gdword")
-        result, err := Translate("zh-CN", text)
-        if err != nil {
-            c.HTML(http.StatusOK, "goldendict.tmpl", gin.H{
-                "Text": err,
-            })
+func (t *Translator) Translate(ctx context.Context, req translator.Request) (translator.Result, error) {
+	prompt := Prompt{
+		SourceLang: dialect.Code(req.SourceLang),
+		TargetLang: dialect.Code(req.TargetLang),
+		Text:       req.Text,
+		Context:    req.Context,
+	}
+	if t.dictionary && req.Style == "" && translator.IsWord(req.Text) {
+		if prompt.TargetLang == "" {
+			prompt.TargetLang = "English"
+		}
+		entry, err := t.lookup(ctx, prompt)
+		if err == nil {
+			return translator.Result{
+				Text:       strings.Join(entry.Senses[0].Translations, "; "),
+				SourceLang: req.SourceLang,
+				TargetLang: req.TargetLang,
+				Entry:      entry.toEntry(req.Text),
+			}, nil
+		}
+		log.Printf("OpenAI dictionary lookup failed, translating instead: %v", err)
+	}
 
-        } else {
-            c.HTML(http.StatusOK, "goldendict.tmpl", gin.H{
-                "Text": result,
-            })
-        }
-    })
+	text, err := t.translate(ctx, req.Style, prompt)
+	if _, ok := err.(*translator.Error); ok {
+		return translator.Result{}, err
+	}
+	if err != nil {
+		return translator.Result{}, translator.Errorf("%v", err)
+	}
+	return translator.Result{
+		Text:       text,
+		SourceLang: req.SourceLang,
+		TargetLang: req.TargetLang,
+	}, nil
 }
 
-func Translate(targetLang string, text string) (string, error) {
-    client := oai.NewClient(appSecret)
-    systemPrompt := fmt.Sprintf("You are a highly skilled translation engine with expertise in the technology sector. Your function is to translate texts accurately into the target %s, maintaining the original format, technical terms, and abbreviations. Do not add any explanations or annotations to the translated text.", targetLang)
-    prompt := fmt.Sprintf("Translate the following source text to %s, Output translation directly without any additional text.\nSource Text: %s,\nTranslated Text:", targetLang, text)
-    resp, err := client.CreateChatCompletion(
-        context.Background(),
+func (t *Translator) translate(ctx context.Context, style string, prompt Prompt) (string, error) {
+	messages, err := t.messages(style, prompt)
+	if err != nil {
+		return "", err
+	}
+	resp, err := t.client.CreateChatCompletion(
+		ctx,
 		oai.ChatCompletionRequest{
-            Model: oai.GPT4o,
-			Messages: []oai.ChatCompletionMessage{
-                {
-                    Role: oai.ChatMessageRoleSystem,
-                    Content: systemPrompt,
-                },
-				{
-					Role:    oai.ChatMessageRoleUser,
-					Content: prompt,
-				},
-			},
+			Model:    t.model,
+			Messages: messages,
 		},
 	)
 
 	if err != nil {
-        return "", fmt.Errorf("OpenAI Error: %v\n", err)
+		return "", fmt.Errorf("OpenAI Error: %v", err)
+	}
+	if len(resp.Choices) == 0 {
+		return "", fmt.Errorf("OpenAI Error: no choices returned")
 	}
 	return resp.Choices[0].Message.Content, nil
 }
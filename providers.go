@@ -0,0 +1,14 @@
+package main
+
+// Providers register themselves with the translator package when imported.
+import (
+	_ "github.com/yangxin0/gd-website-api/baidu"
+	_ "github.com/yangxin0/gd-website-api/bing"
+	_ "github.com/yangxin0/gd-website-api/deepl"
+	_ "github.com/yangxin0/gd-website-api/google"
+	_ "github.com/yangxin0/gd-website-api/libretranslate"
+	_ "github.com/yangxin0/gd-website-api/localdict"
+	_ "github.com/yangxin0/gd-website-api/openai"
+	_ "github.com/yangxin0/gd-website-api/tencent"
+	_ "github.com/yangxin0/gd-website-api/youdao"
+)
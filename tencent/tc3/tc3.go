@@ -0,0 +1,96 @@
+package tc3
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"strconv"
+	"time"
+)
+
+const (
+	algorithm   = "TC3-HMAC-SHA256"
+	contentType = "application/json; charset=utf-8"
+)
+
+/*
+AddAuthHeaders 添加鉴权相关请求头 -
+Authorization : TC3-HMAC-SHA256签名
+X-TC-Action : 接口名称
+X-TC-Timestamp : 当前时间戳(秒)
+X-TC-Version : 接口版本
+X-TC-Region : 地域
+@param secretID  您的SecretId
+@param secretKey 您的SecretKey
+@param service   服务名, 如tmt
+@param host      接口域名, 如tmt.tencentcloudapi.com
+@param action    接口名称, 如TextTranslate
+@param version   接口版本, 如2018-03-21
+@param region    地域, 如ap-guangzhou
+@param payload   JSON请求体
+@param header    请求头表
+*/
+func AddAuthHeaders(secretID string, secretKey string, service string, host string, action string, version string, region string, payload []byte, header map[string][]string) {
+	timestamp := time.Now().Unix()
+	header["Authorization"] = []string{Authorization(secretID, secretKey, service, host, payload, timestamp)}
+	header["Content-Type"] = []string{contentType}
+	header["Host"] = []string{host}
+	header["X-TC-Action"] = []string{action}
+	header["X-TC-Timestamp"] = []string{strconv.FormatInt(timestamp, 10)}
+	header["X-TC-Version"] = []string{version}
+	if region != "" {
+		header["X-TC-Region"] = []string{region}
+	}
+}
+
+/*
+Authorization 生成Authorization请求头 -
+格式 : TC3-HMAC-SHA256 Credential=SecretId/Date/service/tc3_request, SignedHeaders=content-type;host, Signature=签名
+*/
+func Authorization(secretID string, secretKey string, service string, host string, payload []byte, timestamp int64) string {
+	date := time.Unix(timestamp, 0).UTC().Format("2006-01-02")
+	signature := CalculateSignature(secretKey, service, host, payload, timestamp)
+	return algorithm + " Credential=" + secretID + "/" + date + "/" + service + "/tc3_request" +
+		", SignedHeaders=content-type;host, Signature=" + signature
+}
+
+/*
+CalculateSignature 计算TC3-HMAC-SHA256签名 -
+计算方式 :
+CanonicalRequest = POST\n/\n\ncontent-type:...\nhost:...\n\ncontent-type;host\nsha256hex(payload)
+StringToSign = TC3-HMAC-SHA256\ntimestamp\nDate/service/tc3_request\nsha256hex(CanonicalRequest)
+SecretSigning = hmac(hmac(hmac("TC3" + SecretKey, Date), service), "tc3_request")
+Signature = hex(hmac(SecretSigning, StringToSign))
+
+@param secretKey 您的SecretKey
+@param service   服务名
+@param host      接口域名
+@param payload   JSON请求体
+@param timestamp 当前时间戳(秒)
+@return 鉴权签名
+*/
+func CalculateSignature(secretKey string, service string, host string, payload []byte, timestamp int64) string {
+	date := time.Unix(timestamp, 0).UTC().Format("2006-01-02")
+	canonicalRequest := "POST\n/\n\n" +
+		"content-type:" + contentType + "\nhost:" + host + "\n\n" +
+		"content-type;host\n" + sha256hex(payload)
+	credentialScope := date + "/" + service + "/tc3_request"
+	stringToSign := algorithm + "\n" + strconv.FormatInt(timestamp, 10) + "\n" +
+		credentialScope + "\n" + sha256hex([]byte(canonicalRequest))
+
+	secretDate := hmacsha256([]byte("TC3"+secretKey), date)
+	secretService := hmacsha256(secretDate, service)
+	secretSigning := hmacsha256(secretService, "tc3_request")
+	return hex.EncodeToString(hmacsha256(secretSigning, stringToSign))
+}
+
+func sha256hex(data []byte) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+func hmacsha256(key []byte, data string) []byte {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(data))
+	return mac.Sum(nil)
+}
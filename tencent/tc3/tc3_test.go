@@ -0,0 +1,53 @@
+package tc3
+
+import (
+	"strings"
+	"testing"
+)
+
+// Example from the Tencent Cloud API 3.0 signature documentation.
+const (
+	docSecretID  = "AKIDz8krbsJ5yKBZQpn74WFkmLPx3EXAMPLE"
+	docSecretKey = "Gu5t9xGARNpq86cd98joQYCN3EXAMPLE"
+	docPayload   = `{"Limit": 1, "Filters": [{"Values": ["\u672a\u547d\u540d"], "Name": "instance-name"}]}`
+	docTimestamp = 1551113065
+)
+
+func TestCalculateSignature(t *testing.T) {
+	tests := []struct {
+		service, host, payload string
+		want                   string
+	}{
+		{"cvm", "cvm.tencentcloudapi.com", docPayload, "72e494ea809ad7a8c8f7a4507b9bddcbaa8e581f516e8da2f66e2c5a96525168"},
+	}
+	for _, tt := range tests {
+		got := CalculateSignature(docSecretKey, tt.service, tt.host, []byte(tt.payload), docTimestamp)
+		if got != tt.want {
+			t.Errorf("CalculateSignature(%s) = %s, want %s", tt.service, got, tt.want)
+		}
+	}
+}
+
+func TestAuthorization(t *testing.T) {
+	got := Authorization(docSecretID, docSecretKey, "cvm", "cvm.tencentcloudapi.com", []byte(docPayload), docTimestamp)
+	want := "TC3-HMAC-SHA256 Credential=AKIDz8krbsJ5yKBZQpn74WFkmLPx3EXAMPLE/2019-02-25/cvm/tc3_request, " +
+		"SignedHeaders=content-type;host, " +
+		"Signature=72e494ea809ad7a8c8f7a4507b9bddcbaa8e581f516e8da2f66e2c5a96525168"
+	if got != want {
+		t.Errorf("Authorization() = %s, want %s", got, want)
+	}
+}
+
+func TestAddAuthHeaders(t *testing.T) {
+	header := map[string][]string{}
+	AddAuthHeaders(docSecretID, docSecretKey, "tmt", "tmt.tencentcloudapi.com", "TextTranslate", "2018-03-21", "ap-guangzhou", []byte("{}"), header)
+
+	for _, key := range []string{"Authorization", "Content-Type", "Host", "X-TC-Action", "X-TC-Timestamp", "X-TC-Version", "X-TC-Region"} {
+		if len(header[key]) != 1 {
+			t.Errorf("header %s not set", key)
+		}
+	}
+	if !strings.Contains(header["Authorization"][0], "/tmt/tc3_request") {
+		t.Errorf("Authorization = %s", header["Authorization"][0])
+	}
+}
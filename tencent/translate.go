@@ -0,0 +1,173 @@
+package tencent
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/yangxin0/gd-website-api/tencent/tc3"
+	"github.com/yangxin0/gd-website-api/translator"
+	"gopkg.in/ini.v1"
+)
+
+const (
+	service = "tmt"
+	host    = "tmt.tencentcloudapi.com"
+	version = "2018-03-21"
+)
+
+func init() {
+	translator.Register(translator.Provider{
+		Name:  "tencent",
+		Title: "Tencent",
+		New:   New,
+	})
+}
+
+// dialect maps canonical codes to Tencent Machine Translation codes.
+var dialect = translator.Dialect{
+	Codes: map[string]string{
+		"zh":    "zh",
+		"zh-TW": "zh-TW",
+	},
+	Default: func(lang string) string {
+		return strings.SplitN(lang, "-", 2)[0]
+	},
+}
+
+// responseError is the error object of every Tencent Cloud API 3.0 response.
+type responseError struct {
+	Code    string `json:"Code"`
+	Message string `json:"Message"`
+}
+
+// Translation is the response of the TextTranslate action.
+type Translation struct {
+	Response struct {
+		TargetText string         `json:"TargetText"`
+		Source     string         `json:"Source"`
+		Target     string         `json:"Target"`
+		Error      *responseError `json:"Error"`
+	} `json:"Response"`
+}
+
+// BatchTranslation is the response of the TextTranslateBatch action.
+type BatchTranslation struct {
+	Response struct {
+		TargetTextList []string       `json:"TargetTextList"`
+		Source         string         `json:"Source"`
+		Target         string         `json:"Target"`
+		Error          *responseError `json:"Error"`
+	} `json:"Response"`
+}
+
+// Translator uses Tencent Machine Translation (TMT) of Tencent Cloud.
+type Translator struct {
+	secretID  string
+	secretKey string
+	region    string
+	client    *http.Client
+}
+
+func New(cfg *ini.Section) (translator.Translator, error) {
+	secretID := cfg.Key("secret_id").String()
+	secretKey := cfg.Key("secret_key").String()
+	if secretID == "" || secretKey == "" {
+		return nil, fmt.Errorf("secret_id and secret_key are required")
+	}
+	return &Translator{
+		secretID:  secretID,
+		secretKey: secretKey,
+		region:    cfg.Key("region").MustString("ap-guangzhou"),
+		client:    &http.Client{Timeout: 10 * time.Second},
+	}, nil
+}
+
+// call signs the payload for an action and decodes the JSON answer into v.
+func (t *Translator) call(ctx context.Context, action string, payload any, v any) error {
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+	request, err := http.NewRequestWithContext(ctx, "POST", "https://"+host, bytes.NewReader(data))
+	if err != nil {
+		return err
+	}
+	tc3.AddAuthHeaders(t.secretID, t.secretKey, service, host, action, version, t.region, data, request.Header)
+
+	resp, err := t.client.Do(request)
+	if err != nil {
+		return translator.Errorf("Tencent API request failed: %v", err)
+	}
+	defer resp.Body.Close()
+	data, err = io.ReadAll(resp.Body)
+	if err != nil {
+		return translator.Errorf("Tencent API request failed: %v", err)
+	}
+	if err := json.Unmarshal(data, v); err != nil {
+		return translator.Errorf("Tencent API returned invalid JSON: %v", err)
+	}
+	return nil
+}
+
+func source(req translator.Request) string {
+	if req.SourceLang == "" {
+		return "auto"
+	}
+	return dialect.Code(req.SourceLang)
+}
+
+func (t *Translator) Translate(ctx context.Context, req translator.Request) (translator.Result, error) {
+	var result Translation
+	err := t.call(ctx, "TextTranslate", map[string]any{
+		"SourceText": req.Text,
+		"Source":     source(req),
+		"Target":     dialect.Code(req.TargetLang),
+		"ProjectId":  0,
+	}, &result)
+	if err != nil {
+		return translator.Result{}, err
+	}
+	if e := result.Response.Error; e != nil {
+		return translator.Result{}, translator.Errorf("Tencent API error %s: %s", e.Code, e.Message)
+	}
+	return translator.Result{
+		Text:       result.Response.TargetText,
+		SourceLang: dialect.Canonical(result.Response.Source),
+		TargetLang: dialect.Canonical(result.Response.Target),
+	}, nil
+}
+
+// TranslateBatch translates all texts with a single TextTranslateBatch call.
+func (t *Translator) TranslateBatch(ctx context.Context, req translator.Request, texts []string) ([]translator.Result, error) {
+	var result BatchTranslation
+	err := t.call(ctx, "TextTranslateBatch", map[string]any{
+		"SourceTextList": texts,
+		"Source":         source(req),
+		"Target":         dialect.Code(req.TargetLang),
+		"ProjectId":      0,
+	}, &result)
+	if err != nil {
+		return nil, err
+	}
+	if e := result.Response.Error; e != nil {
+		return nil, translator.Errorf("Tencent API error %s: %s", e.Code, e.Message)
+	}
+	if len(result.Response.TargetTextList) != len(texts) {
+		return nil, translator.Errorf("Tencent API returned a wrong number of translations")
+	}
+	results := make([]translator.Result, len(texts))
+	for i, text := range result.Response.TargetTextList {
+		results[i] = translator.Result{
+			Text:       text,
+			SourceLang: dialect.Canonical(result.Response.Source),
+			TargetLang: dialect.Canonical(result.Response.Target),
+		}
+	}
+	return results, nil
+}
@@ -0,0 +1,129 @@
+package translator
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"gopkg.in/ini.v1"
+)
+
+// ErrTimeout is reported for providers that miss the /all deadline.
+var ErrTimeout = &Error{Code: http.StatusGatewayTimeout, Message: "Timed out"}
+
+// Section is the outcome of one provider on the /all page.
+type Section struct {
+	Title   string
+	Request Request
+	Result  Result
+	Error   error
+	Latency time.Duration
+}
+
+func setupAll(route *gin.Engine, cfg *ini.Section) {
+	if !cfg.Key("enable").MustBool() {
+		fmt.Println("Dict: All Disabled")
+		return
+	}
+	fmt.Println("Dict: All Enabled")
+	timeout := time.Duration(cfg.Key("timeout").MustInt(10)) * time.Second
+	route.GET("/all", allHandler(timeout))
+}
+
+func allHandler(timeout time.Duration) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		ctx, cancel := context.WithTimeout(c.Request.Context(), timeout)
+		defer cancel()
+
+		sections := make([]Section, len(enabled))
+		var wg sync.WaitGroup
+		for i, e := range enabled {
+			req := RequestFromQuery(c, e.defaults)
+			wg.Add(1)
+			go func(i int, e *entry) {
+				defer wg.Done()
+				start := time.Now()
+				result, err := translateWithin(ctx, e, req)
+				sections[i] = Section{
+					Title:   e.Title,
+					Request: req,
+					Result:  result,
+					Error:   err,
+					Latency: time.Since(start),
+				}
+			}(i, e)
+		}
+		wg.Wait()
+		renderAll(c, OutputFormat(c, FormatHTML), sections)
+	}
+}
+
+// renderAll writes the /all page in format. In JSON every provider has the
+// response or error payload of its own route.
+func renderAll(c *gin.Context, format string, sections []Section) {
+	switch format {
+	case FormatJSON:
+		results := make([]any, len(sections))
+		for i, s := range sections {
+			if s.Error != nil {
+				results[i] = ErrorResponse(s.Error, s.Result.Provider)
+			} else {
+				results[i] = newResponse(s.Request, s.Result, s.Latency)
+			}
+		}
+		c.JSON(http.StatusOK, gin.H{
+			"code":    http.StatusOK,
+			"results": results,
+		})
+	case FormatText, FormatMarkdown:
+		var b strings.Builder
+		for i, s := range sections {
+			if i > 0 {
+				b.WriteString("\n")
+			}
+			switch {
+			case format == FormatText && s.Error != nil:
+				fmt.Fprintf(&b, "[%s]\n%s\n", s.Title, s.Error)
+			case format == FormatText:
+				fmt.Fprintf(&b, "[%s]\n%s\n", s.Title, s.Result.Text)
+			case s.Error != nil:
+				fmt.Fprintf(&b, "## %s\n\n*%s*\n", s.Title, s.Error)
+			default:
+				fmt.Fprintf(&b, "## %s\n\n%s", s.Title, Markdown(s.Result))
+			}
+		}
+		if format == FormatText {
+			c.String(http.StatusOK, b.String())
+		} else {
+			c.Data(http.StatusOK, mimeMarkdown+"; charset=utf-8", []byte(b.String()))
+		}
+	default:
+		c.HTML(http.StatusOK, "all.tmpl", gin.H{
+			"Sections": sections,
+		})
+	}
+}
+
+// translateWithin returns ErrTimeout once ctx is done, even if the provider
+// ignores ctx and keeps running in the background.
+func translateWithin(ctx context.Context, e *entry, req Request) (Result, error) {
+	type outcome struct {
+		result Result
+		err    error
+	}
+	done := make(chan outcome, 1)
+	go func() {
+		result, err := Translate(ctx, e.Name, e.translator, req)
+		done <- outcome{result, err}
+	}()
+	select {
+	case o := <-done:
+		return o.result, o.err
+	case <-ctx.Done():
+		return Result{Provider: e.Name}, ErrTimeout
+	}
+}
@@ -0,0 +1,141 @@
+package translator
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// BatchTranslator is implemented by providers that translate several texts
+// in one call. The languages and options of req apply to every text.
+type BatchTranslator interface {
+	TranslateBatch(ctx context.Context, req Request, texts []string) ([]Result, error)
+}
+
+// Detector is implemented by providers that can detect the language of a
+// text. confidence is between 0 and 1.
+type Detector interface {
+	Detect(ctx context.Context, text string) (lang string, confidence float64, err error)
+}
+
+// TranslateBatch translates texts with one call when the provider supports
+// it, and one by one otherwise.
+func TranslateBatch(ctx context.Context, name string, t Translator, req Request, texts []string) ([]Result, error) {
+	if b, ok := t.(BatchTranslator); ok {
+		results, err := b.TranslateBatch(ctx, req, texts)
+		for i := range results {
+			results[i].Provider = name
+		}
+		return results, err
+	}
+
+	results := make([]Result, len(texts))
+	for i, text := range texts {
+		req.Text = text
+		result, err := Translate(ctx, name, t, req)
+		if err != nil {
+			return nil, err
+		}
+		results[i] = result
+	}
+	return results, nil
+}
+
+// TranslateBatch serves cached texts from the cache and translates the rest
+// in one batch.
+func (c *cached) TranslateBatch(ctx context.Context, req Request, texts []string) ([]Result, error) {
+	results := make([]Result, len(texts))
+	var missing []string
+	var missingIndex []int
+	for i, text := range texts {
+		req.Text = text
+		if result, ok := c.store.get(cacheKey(c.name, req)); ok {
+			results[i] = result
+			continue
+		}
+		missing = append(missing, text)
+		missingIndex = append(missingIndex, i)
+	}
+	if len(missing) == 0 {
+		return results, nil
+	}
+
+	translated, err := TranslateBatch(ctx, c.name, c.next, req, missing)
+	if err != nil {
+		return nil, err
+	}
+	for i, result := range translated {
+		results[missingIndex[i]] = result
+		if result.Text != "" {
+			req.Text = missing[i]
+			c.store.set(cacheKey(c.name, req), result)
+		}
+	}
+	return results, nil
+}
+
+// batchRequest is the body of POST /<name>/batch.
+type batchRequest struct {
+	Texts      []string `json:"texts" binding:"required"`
+	SourceLang string   `json:"source_lang"`
+	TargetLang string   `json:"target_lang"`
+	Style      string   `json:"style"`
+	TextFormat string   `json:"text_format"`
+}
+
+// batchHandler serves POST /<name>/batch, translating a JSON list of texts.
+func batchHandler(e *entry) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var body batchRequest
+		if err := c.ShouldBindJSON(&body); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"code":    http.StatusBadRequest,
+				"message": err.Error(),
+			})
+			return
+		}
+		req := e.defaults
+		if body.SourceLang != "" {
+			req.SourceLang = Normalize(body.SourceLang)
+		}
+		if body.TargetLang != "" {
+			req.TargetLang = Normalize(body.TargetLang)
+		}
+		req.Style = body.Style
+		req.Format = body.TextFormat
+
+		results, err := TranslateBatch(c.Request.Context(), e.Name, e.translator, req, body.Texts)
+		if err != nil {
+			c.JSON(StatusCode(err), ErrorResponse(err, e.Name))
+			return
+		}
+		c.JSON(http.StatusOK, gin.H{
+			"code":    http.StatusOK,
+			"results": results,
+		})
+	}
+}
+
+// detectHandler serves /<name>/detect?gdword= for providers that implement
+// Detector.
+func detectHandler(e *entry) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		text := c.Query("gdword")
+		if text == "" {
+			c.JSON(ErrNoText.Code, ErrorResponse(ErrNoText, e.Name))
+			return
+		}
+		lang, confidence, err := e.detector.Detect(c.Request.Context(), text)
+		if err != nil {
+			c.JSON(StatusCode(err), ErrorResponse(err, e.Name))
+			return
+		}
+		c.JSON(http.StatusOK, gin.H{
+			"code":       http.StatusOK,
+			"provider":   e.Name,
+			"language":   lang,
+			"confidence": confidence,
+		})
+	}
+}
@@ -0,0 +1,180 @@
+package translator
+
+import (
+	"context"
+	"crypto/subtle"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/yangxin0/gd-website-api/cache"
+	"gopkg.in/ini.v1"
+)
+
+// store layers the in-memory cache in front of the persistent one. Either
+// may be nil.
+type store struct {
+	memory *cache.LRU
+	disk   *cache.Disk
+}
+
+func (s *store) get(key string) (Result, bool) {
+	if s.memory != nil {
+		if v, ok := s.memory.Get(key); ok {
+			return v.(Result), true
+		}
+	}
+	if s.disk != nil {
+		if data, ok := s.disk.Get(key); ok {
+			var result Result
+			if json.Unmarshal(data, &result) == nil && result.Text != "" {
+				if s.memory != nil {
+					s.memory.Set(key, result)
+				}
+				return result, true
+			}
+		}
+	}
+	return Result{}, false
+}
+
+func (s *store) set(key string, result Result) {
+	if s.memory != nil {
+		s.memory.Set(key, result)
+	}
+	if s.disk != nil {
+		data, err := json.Marshal(result)
+		if err == nil {
+			err = s.disk.Set(key, data)
+		}
+		if err != nil {
+			fmt.Printf("Cache: write failed: %v\n", err)
+		}
+	}
+}
+
+// purge removes the entries of provider and/or text; an empty argument
+// matches everything.
+func (s *store) purge(provider string, text string) (int, error) {
+	text = normalizeText(text)
+	match := func(key string) bool {
+		parts := strings.Split(key, "\x1f")
+		return (provider == "" || parts[0] == provider) && (text == "" || parts[len(parts)-1] == text)
+	}
+	removed := 0
+	if s.memory != nil {
+		removed = s.memory.Purge(match)
+	}
+	if s.disk != nil {
+		n, err := s.disk.Purge(match)
+		if err != nil {
+			return removed, err
+		}
+		if n > removed {
+			removed = n
+		}
+	}
+	return removed, nil
+}
+
+// cached serves repeated lookups from the cache shared by all providers.
+type cached struct {
+	name  string
+	next  Translator
+	store *store
+}
+
+func (c *cached) Translate(ctx context.Context, req Request) (Result, error) {
+	key := cacheKey(c.name, req)
+	if result, ok := c.store.get(key); ok {
+		return result, nil
+	}
+	result, err := c.next.Translate(ctx, req)
+	if err == nil && result.Text != "" {
+		c.store.set(key, result)
+	}
+	return result, err
+}
+
+// normalizeText removes surrounding and repeated whitespace.
+func normalizeText(text string) string {
+	return strings.Join(strings.Fields(text), " ")
+}
+
+// cacheKey identifies a lookup by provider, languages, style, context,
+// format and normalized text. The provider comes first and the text last so entries can
+// be purged by either.
+func cacheKey(name string, req Request) string {
+	return strings.Join([]string{name, req.SourceLang, req.TargetLang, req.Style, req.Context, req.Format, normalizeText(req.Text)}, "\x1f")
+}
+
+func setupCache(route *gin.Engine, cfg *ini.Section) *store {
+	s := &store{}
+	if cfg.Key("enable").MustBool() {
+		size := cfg.Key("size").MustInt(1000)
+		ttl := time.Duration(cfg.Key("ttl").MustInt(86400)) * time.Second
+		fmt.Printf("Cache: %v entries, TTL %v\n", size, ttl)
+		s.memory = cache.NewLRU(size, ttl)
+	} else {
+		fmt.Println("Cache: Disabled")
+	}
+	if path := cfg.Key("path").String(); path != "" {
+		size := cfg.Key("disk_size").MustInt(100000)
+		ttl := time.Duration(cfg.Key("disk_ttl").MustInt(90*86400)) * time.Second
+		disk, err := cache.OpenDisk(path, size, ttl)
+		if err != nil {
+			fmt.Printf("Disk Cache: Failed: %v\n", err)
+		} else {
+			fmt.Printf("Disk Cache: %v, %v entries, TTL %v\n", path, size, ttl)
+			s.disk = disk
+		}
+	}
+	if s.memory == nil && s.disk == nil {
+		return nil
+	}
+
+	route.GET("/cache/stats", func(c *gin.Context) {
+		stats := gin.H{}
+		if s.memory != nil {
+			stats["memory"] = s.memory.Stats()
+		}
+		if s.disk != nil {
+			stats["disk"] = s.disk.Stats()
+		}
+		c.JSON(http.StatusOK, stats)
+	})
+
+	// Without an admin_token the purge route is not served at all, since
+	// CORS lets any web page send the DELETE.
+	token := cfg.Key("admin_token").String()
+	if token == "" {
+		fmt.Println("Cache Admin: Disabled (no admin_token)")
+		return s
+	}
+	route.DELETE("/admin/cache", func(c *gin.Context) {
+		auth := c.GetHeader("Authorization")
+		if subtle.ConstantTimeCompare([]byte(auth), []byte("Bearer "+token)) != 1 {
+			c.JSON(http.StatusUnauthorized, gin.H{
+				"code":    http.StatusUnauthorized,
+				"message": "Invalid access token",
+			})
+			return
+		}
+		removed, err := s.purge(c.Query("provider"), c.Query("text"))
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{
+				"code":    http.StatusInternalServerError,
+				"message": err.Error(),
+			})
+			return
+		}
+		c.JSON(http.StatusOK, gin.H{
+			"code":    http.StatusOK,
+			"removed": removed,
+		})
+	})
+	return s
+}
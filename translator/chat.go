@@ -0,0 +1,213 @@
+package translator
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"gopkg.in/ini.v1"
+)
+
+// chatMessage is a message of a chat completion request. Content is either
+// a string or a list of parts of which the text parts are used.
+type chatMessage struct {
+	Role    string          `json:"role"`
+	Content json.RawMessage `json:"content"`
+}
+
+// text returns the text of the message content.
+func (m chatMessage) text() string {
+	var s string
+	if json.Unmarshal(m.Content, &s) == nil {
+		return s
+	}
+	var parts []struct {
+		Type string `json:"type"`
+		Text string `json:"text"`
+	}
+	json.Unmarshal(m.Content, &parts)
+	var texts []string
+	for _, part := range parts {
+		if part.Type == "text" {
+			texts = append(texts, part.Text)
+		}
+	}
+	return strings.Join(texts, "\n")
+}
+
+type chatRequest struct {
+	Model    string        `json:"model" binding:"required"`
+	Messages []chatMessage `json:"messages" binding:"required"`
+	Stream   bool          `json:"stream"`
+}
+
+// chatAPI serves /v1/chat/completions and /v1/models in the shape of the
+// OpenAI API. Each enabled provider is a model; "<provider>:<lang>" also
+// selects the target language, e.g. "deepl:ja". The text to translate is
+// the last user message.
+type chatAPI struct {
+	token string
+}
+
+func setupChatAPI(route *gin.Engine, cfg *ini.Section) {
+	if !cfg.Key("enable").MustBool() {
+		fmt.Println("Dict: OpenAI API Disabled")
+		return
+	}
+	fmt.Println("Dict: OpenAI API Enabled")
+	api := &chatAPI{token: cfg.Key("token").String()}
+	v1 := route.Group("/v1", api.authorize)
+	v1.POST("/chat/completions", api.completionsHandler)
+	v1.GET("/models", api.modelsHandler)
+}
+
+// chatError writes an error in the shape of the OpenAI API.
+func chatError(c *gin.Context, code int, message string) {
+	c.AbortWithStatusJSON(code, gin.H{
+		"error": gin.H{
+			"message": message,
+			"type":    "invalid_request_error",
+			"code":    code,
+		},
+	})
+}
+
+// authorize checks "Authorization: Bearer <token>" when a token is
+// configured.
+func (api *chatAPI) authorize(c *gin.Context) {
+	if api.token != "" && c.GetHeader("Authorization") != "Bearer "+api.token {
+		chatError(c, http.StatusUnauthorized, "Invalid access token")
+	}
+}
+
+func (api *chatAPI) modelsHandler(c *gin.Context) {
+	models := make([]gin.H, len(enabled))
+	for i, e := range enabled {
+		models[i] = gin.H{
+			"id":       e.Name,
+			"object":   "model",
+			"created":  0,
+			"owned_by": e.Title,
+		}
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"object": "list",
+		"data":   models,
+	})
+}
+
+func (api *chatAPI) completionsHandler(c *gin.Context) {
+	var body chatRequest
+	if err := c.ShouldBindJSON(&body); err != nil {
+		chatError(c, http.StatusBadRequest, err.Error())
+		return
+	}
+	name, lang, _ := strings.Cut(body.Model, ":")
+	var e *entry
+	for _, candidate := range enabled {
+		if candidate.Name == name {
+			e = candidate
+		}
+	}
+	if e == nil {
+		chatError(c, http.StatusNotFound, fmt.Sprintf("The model `%s` does not exist", body.Model))
+		return
+	}
+	req := e.defaults
+	if lang != "" {
+		req.TargetLang = Normalize(lang)
+	}
+	for i := len(body.Messages) - 1; i >= 0; i-- {
+		if body.Messages[i].Role == "user" {
+			req.Text = body.Messages[i].text()
+			break
+		}
+	}
+
+	id := "chatcmpl-" + strconv.FormatInt(time.Now().UnixNano(), 36)
+	created := time.Now().Unix()
+	if body.Stream {
+		api.stream(c, e, req, id, created, body.Model)
+		return
+	}
+
+	result, err := Translate(c.Request.Context(), e.Name, e.translator, req)
+	if err != nil {
+		chatError(c, StatusCode(err), err.Error())
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"id":      id,
+		"object":  "chat.completion",
+		"created": created,
+		"model":   body.Model,
+		"choices": []gin.H{{
+			"index": 0,
+			"message": gin.H{
+				"role":    "assistant",
+				"content": result.Text,
+			},
+			"finish_reason": "stop",
+		}},
+		"usage": gin.H{
+			"prompt_tokens":     0,
+			"completion_tokens": 0,
+			"total_tokens":      0,
+		},
+	})
+}
+
+// stream sends the translation as chat.completion.chunk events terminated
+// by "data: [DONE]". Errors after the first chunk are sent as an error
+// event, since the status is already written.
+func (api *chatAPI) stream(c *gin.Context, e *entry, req Request, id string, created int64, model string) {
+	ctx := c.Request.Context()
+	chunk := func(delta gin.H, finishReason any) {
+		data, _ := json.Marshal(gin.H{
+			"id":      id,
+			"object":  "chat.completion.chunk",
+			"created": created,
+			"model":   model,
+			"choices": []gin.H{{
+				"index":         0,
+				"delta":         delta,
+				"finish_reason": finishReason,
+			}},
+		})
+		fmt.Fprintf(c.Writer, "data: %s\n\n", data)
+		c.Writer.Flush()
+	}
+
+	started := false
+	_, err := TranslateStream(ctx, e.Name, e.translator, req, func(delta string) error {
+		if !started {
+			c.Header("Content-Type", "text/event-stream")
+			c.Header("Cache-Control", "no-cache")
+			c.Header("X-Accel-Buffering", "no")
+			c.Status(http.StatusOK)
+			chunk(gin.H{"role": "assistant", "content": ""}, nil)
+			started = true
+		}
+		chunk(gin.H{"content": delta}, nil)
+		return ctx.Err()
+	})
+	if ctx.Err() != nil {
+		return
+	}
+	if err != nil {
+		if !started {
+			chatError(c, StatusCode(err), err.Error())
+			return
+		}
+		data, _ := json.Marshal(gin.H{"error": gin.H{"message": err.Error(), "code": StatusCode(err)}})
+		fmt.Fprintf(c.Writer, "data: %s\n\n", data)
+	} else {
+		chunk(gin.H{}, "stop")
+	}
+	fmt.Fprint(c.Writer, "data: [DONE]\n\n")
+	c.Writer.Flush()
+}
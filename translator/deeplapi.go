@@ -0,0 +1,152 @@
+package translator
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+	"sync/atomic"
+
+	"github.com/gin-gonic/gin"
+	"gopkg.in/ini.v1"
+)
+
+// deepLRequest holds the parameters of the official DeepL /v2/translate,
+// sent either as a form or as JSON. formality is accepted for compatibility
+// but ignored, as the providers have no equivalent.
+type deepLRequest struct {
+	Text        []string `json:"text" form:"text"`
+	SourceLang  string   `json:"source_lang" form:"source_lang"`
+	TargetLang  string   `json:"target_lang" form:"target_lang"`
+	Formality   string   `json:"formality" form:"formality"`
+	TagHandling string   `json:"tag_handling" form:"tag_handling"`
+}
+
+type deepLTranslation struct {
+	DetectedSourceLanguage string `json:"detected_source_language"`
+	Text                   string `json:"text"`
+}
+
+// deepLAPI serves /v2/translate and /v2/usage in the shape of the official
+// DeepL API, translating with one of the enabled providers.
+type deepLAPI struct {
+	provider string
+	authKey  string
+	limit    int64
+	count    atomic.Int64
+}
+
+func setupDeepLAPI(route *gin.Engine, cfg *ini.Section) {
+	if !cfg.Key("enable").MustBool() {
+		fmt.Println("Dict: DeepL API Disabled")
+		return
+	}
+	api := &deepLAPI{
+		provider: cfg.Key("provider").MustString("deepl"),
+		authKey:  cfg.Key("auth_key").String(),
+		limit:    cfg.Key("character_limit").MustInt64(1000000000),
+	}
+	if api.entry() == nil {
+		fmt.Printf("Dict: DeepL API Failed: provider %s is not enabled\n", api.provider)
+		return
+	}
+	fmt.Printf("Dict: DeepL API Enabled (%s)\n", api.provider)
+	v2 := route.Group("/v2", api.authorize)
+	v2.POST("/translate", api.translateHandler)
+	v2.GET("/usage", api.usageHandler)
+	v2.POST("/usage", api.usageHandler)
+}
+
+func (api *deepLAPI) entry() *entry {
+	for _, e := range enabled {
+		if e.Name == api.provider {
+			return e
+		}
+	}
+	return nil
+}
+
+// authorize checks the "Authorization: DeepL-Auth-Key <key>" header, or the
+// legacy auth_key parameter, when an auth_key is configured.
+func (api *deepLAPI) authorize(c *gin.Context) {
+	if api.authKey == "" {
+		return
+	}
+	key := strings.TrimPrefix(c.GetHeader("Authorization"), "DeepL-Auth-Key ")
+	if key == "" {
+		key = c.Request.FormValue("auth_key")
+	}
+	if key != api.authKey {
+		c.AbortWithStatusJSON(http.StatusForbidden, gin.H{
+			"message": "Authorization failed. Please supply a valid auth_key parameter.",
+		})
+	}
+}
+
+func (api *deepLAPI) translateHandler(c *gin.Context) {
+	var body deepLRequest
+	var err error
+	if c.ContentType() == gin.MIMEJSON {
+		err = c.ShouldBindJSON(&body)
+	} else {
+		err = c.ShouldBind(&body)
+		// Some clients send the texts as text[]=...
+		body.Text = append(body.Text, c.PostFormArray("text[]")...)
+	}
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"message": err.Error()})
+		return
+	}
+	if len(body.Text) == 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"message": "Parameter 'text' not specified."})
+		return
+	}
+	if body.TargetLang == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"message": "Value for 'target_lang' not supported."})
+		return
+	}
+
+	if api.count.Load() >= api.limit {
+		c.JSON(456, gin.H{"message": "Quota exceeded. The character limit has been reached."})
+		return
+	}
+
+	e := api.entry()
+	req := Request{
+		SourceLang: Normalize(body.SourceLang),
+		TargetLang: Normalize(body.TargetLang),
+	}
+	if body.TagHandling == "html" || body.TagHandling == "xml" {
+		req.Format = "html"
+	}
+	results, err := TranslateBatch(c.Request.Context(), e.Name, e.translator, req, body.Text)
+	if err != nil {
+		c.JSON(StatusCode(err), gin.H{"message": err.Error()})
+		return
+	}
+
+	translations := make([]deepLTranslation, len(results))
+	for i, result := range results {
+		api.count.Add(int64(len([]rune(body.Text[i]))))
+		sourceLang := result.SourceLang
+		if sourceLang == "" {
+			sourceLang = req.SourceLang
+		}
+		// DeepL reports source languages without a region, e.g. "ZH" or "PT".
+		primary, _, _ := strings.Cut(sourceLang, "-")
+		translations[i] = deepLTranslation{
+			DetectedSourceLanguage: strings.ToUpper(primary),
+			Text:                   result.Text,
+		}
+	}
+	c.JSON(http.StatusOK, gin.H{"translations": translations})
+}
+
+// usageHandler reports the characters translated through /v2/translate
+// since startup against the configured character_limit, after which
+// /v2/translate answers 456 like DeepL does.
+func (api *deepLAPI) usageHandler(c *gin.Context) {
+	c.JSON(http.StatusOK, gin.H{
+		"character_count": api.count.Load(),
+		"character_limit": api.limit,
+	})
+}
@@ -0,0 +1,71 @@
+package translator
+
+import "strings"
+
+// Entry is a dictionary entry for a single word, rendered by the entry
+// template below the translation.
+type Entry struct {
+	Word      string     `json:"word"`
+	Lemma     string     `json:"lemma,omitempty"`
+	Phonetics []Phonetic `json:"phonetics,omitempty"`
+	Audio     []Audio    `json:"audio,omitempty"`
+	Senses    []Sense    `json:"senses,omitempty"`
+	Forms     []Form     `json:"forms,omitempty"`
+	Phrases   []Phrase   `json:"phrases,omitempty"`
+	Synonyms  []string   `json:"synonyms,omitempty"`
+}
+
+// Phonetic is a pronunciation such as the US or UK IPA transcription.
+type Phonetic struct {
+	Label string `json:"label"`
+	IPA   string `json:"ipa"`
+}
+
+// Audio links to a pronunciation served by the provider.
+type Audio struct {
+	Label string `json:"label"`
+	URL   string `json:"url"`
+}
+
+// Sense is one meaning of the word, optionally tagged with its part of
+// speech, with translations and examples when the provider has them.
+type Sense struct {
+	PartOfSpeech string    `json:"part_of_speech"`
+	Meaning      string    `json:"meaning"`
+	Translations []string  `json:"translations,omitempty"`
+	Examples     []Example `json:"examples,omitempty"`
+}
+
+// Example is an example sentence and its translation.
+type Example struct {
+	Text        string `json:"text"`
+	Translation string `json:"translation"`
+}
+
+// Form is an inflected form such as the plural or past tense.
+type Form struct {
+	Name  string `json:"name"`
+	Value string `json:"value"`
+}
+
+// Phrase is a collocation or idiom containing the word.
+type Phrase struct {
+	Text     string   `json:"text"`
+	Meanings []string `json:"meanings,omitempty"`
+}
+
+// ParseSense splits explanations such as "adj. 好的；优秀的" into part of
+// speech and meaning.
+func ParseSense(explain string) Sense {
+	pos, meaning, found := strings.Cut(explain, ". ")
+	if !found || len(pos) > 8 || strings.ContainsAny(pos, " ；;,，") {
+		return Sense{Meaning: explain}
+	}
+	return Sense{PartOfSpeech: pos + ".", Meaning: meaning}
+}
+
+// IsWord reports whether text is a single word rather than a phrase or
+// sentence.
+func IsWord(text string) bool {
+	return len(strings.Fields(text)) == 1
+}
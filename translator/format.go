@@ -0,0 +1,171 @@
+package translator
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Output formats of the provider routes, chosen by the format query
+// parameter or, without one, by the Accept header. HTML is the default for
+// GoldenDict. The format of the input text is set by text_format instead.
+const (
+	FormatHTML     = "html"
+	FormatJSON     = "json"
+	FormatText     = "text"
+	FormatMarkdown = "md"
+)
+
+const mimeMarkdown = "text/markdown"
+
+// OutputFormat returns the output format requested by c, or defaultFormat
+// when neither the format parameter nor the Accept header picks one.
+func OutputFormat(c *gin.Context, defaultFormat string) string {
+	switch format := c.Query("format"); format {
+	case FormatHTML, FormatJSON, FormatText, FormatMarkdown:
+		return format
+	}
+	offers := map[string]string{
+		FormatHTML:     gin.MIMEHTML,
+		FormatJSON:     gin.MIMEJSON,
+		FormatText:     gin.MIMEPlain,
+		FormatMarkdown: mimeMarkdown,
+	}
+	// NegotiateFormat prefers the first offer for */* and missing headers.
+	mimes := []string{offers[defaultFormat]}
+	for _, format := range []string{FormatHTML, FormatJSON, FormatText, FormatMarkdown} {
+		if format != defaultFormat {
+			mimes = append(mimes, offers[format])
+		}
+	}
+	negotiated := c.NegotiateFormat(mimes...)
+	for format, mime := range offers {
+		if mime == negotiated {
+			return format
+		}
+	}
+	return defaultFormat
+}
+
+// Response is the JSON body of a provider route: the full Result with the
+// detected language, when the request asked for detection, and the time the
+// lookup took.
+type Response struct {
+	Code int `json:"code"`
+	Result
+	DetectedSourceLang string `json:"detected_source_lang,omitempty"`
+	LatencyMS          int64  `json:"latency_ms"`
+}
+
+// ErrorResponse is the JSON body of a failed lookup, shared with the batch,
+// detect, stream and provider specific routes.
+func ErrorResponse(err error, provider string) gin.H {
+	return gin.H{
+		"code":     StatusCode(err),
+		"message":  err.Error(),
+		"provider": provider,
+	}
+}
+
+func newResponse(req Request, result Result, latency time.Duration) Response {
+	response := Response{
+		Code:      http.StatusOK,
+		Result:    result,
+		LatencyMS: latency.Milliseconds(),
+	}
+	if req.SourceLang == "" {
+		response.DetectedSourceLang = result.SourceLang
+	}
+	return response
+}
+
+// render writes the outcome of a lookup in format.
+func render(c *gin.Context, format string, req Request, result Result, err error, latency time.Duration) {
+	if err != nil {
+		switch format {
+		case FormatJSON:
+			c.JSON(StatusCode(err), ErrorResponse(err, result.Provider))
+		default:
+			c.String(StatusCode(err), err.Error())
+		}
+		return
+	}
+	switch format {
+	case FormatJSON:
+		c.JSON(http.StatusOK, newResponse(req, result, latency))
+	case FormatText:
+		c.String(http.StatusOK, result.Text)
+	case FormatMarkdown:
+		c.Data(http.StatusOK, mimeMarkdown+"; charset=utf-8", []byte(Markdown(result)))
+	default:
+		c.HTML(http.StatusOK, "goldendict.tmpl", gin.H{
+			"Result": result,
+		})
+	}
+}
+
+// Markdown renders a result like the result template does in HTML.
+func Markdown(result Result) string {
+	var b strings.Builder
+	b.WriteString(result.Text + "\n")
+	if len(result.Alternatives) > 0 {
+		b.WriteString("\n")
+		for i, alternative := range result.Alternatives {
+			fmt.Fprintf(&b, "%d. %s\n", i+1, alternative)
+		}
+	}
+	if e := result.Entry; e != nil {
+		b.WriteString("\n### " + e.Word)
+		if e.Lemma != "" && e.Lemma != e.Word {
+			b.WriteString(" → " + e.Lemma)
+		}
+		b.WriteString("\n\n")
+		for _, p := range e.Phonetics {
+			if p.Label != "" {
+				b.WriteString(p.Label + " ")
+			}
+			b.WriteString("/" + p.IPA + "/  \n")
+		}
+		for _, s := range e.Senses {
+			b.WriteString("- ")
+			if s.PartOfSpeech != "" {
+				b.WriteString("*" + s.PartOfSpeech + "* ")
+			}
+			b.WriteString(s.Meaning)
+			if len(s.Translations) > 0 {
+				b.WriteString(" — " + strings.Join(s.Translations, "; "))
+			}
+			b.WriteString("\n")
+			for _, example := range s.Examples {
+				b.WriteString("  - " + example.Text)
+				if example.Translation != "" {
+					b.WriteString(" — " + example.Translation)
+				}
+				b.WriteString("\n")
+			}
+		}
+		if len(e.Forms) > 0 {
+			forms := make([]string, len(e.Forms))
+			for i, f := range e.Forms {
+				forms[i] = f.Name + ": " + f.Value
+			}
+			b.WriteString("\n" + strings.Join(forms, ", ") + "\n")
+		}
+		if len(e.Phrases) > 0 {
+			b.WriteString("\n")
+			for _, p := range e.Phrases {
+				b.WriteString("- **" + p.Text + "** " + strings.Join(p.Meanings, "; ") + "\n")
+			}
+		}
+		if len(e.Synonyms) > 0 {
+			b.WriteString("\nSynonyms: " + strings.Join(e.Synonyms, ", ") + "\n")
+		}
+	}
+	if result.SourceLang != "" {
+		fmt.Fprintf(&b, "\n*%s → %s*\n", result.SourceLang, result.TargetLang)
+	}
+	return b.String()
+}
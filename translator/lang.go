@@ -0,0 +1,71 @@
+package translator
+
+import "strings"
+
+// aliases folds the spellings used by different providers into one
+// canonical code.
+var aliases = map[string]string{
+	"auto":    "",
+	"zh-cn":   "zh",
+	"zh-sg":   "zh",
+	"zh-chs":  "zh",
+	"zh-hans": "zh",
+	"zh-cht":  "zh-TW",
+	"zh-hant": "zh-TW",
+	"pt-pt":   "pt",
+	"iw":      "he",
+	"jw":      "jv",
+	"nb":      "no",
+}
+
+// Normalize returns the canonical form of a language code: a lower case
+// primary subtag followed by an upper case region, e.g. "en", "zh-TW" or
+// "pt-BR". An empty result means the language should be detected.
+func Normalize(code string) string {
+	code = strings.ToLower(strings.TrimSpace(strings.ReplaceAll(code, "_", "-")))
+	if alias, ok := aliases[code]; ok {
+		return alias
+	}
+	parts := strings.Split(code, "-")
+	for i := 1; i < len(parts); i++ {
+		switch len(parts[i]) {
+		case 2:
+			parts[i] = strings.ToUpper(parts[i])
+		case 4:
+			parts[i] = strings.ToUpper(parts[i][:1]) + parts[i][1:]
+		}
+	}
+	return strings.Join(parts, "-")
+}
+
+// Dialect maps canonical language codes to the codes a provider expects.
+// Codes missing from Codes are converted by Default, or passed through
+// unchanged when Default is nil.
+type Dialect struct {
+	Codes   map[string]string
+	Default func(lang string) string
+}
+
+// Code converts a canonical language code to the provider dialect.
+func (d Dialect) Code(lang string) string {
+	if lang == "" {
+		return ""
+	}
+	if code, ok := d.Codes[lang]; ok {
+		return code
+	}
+	if d.Default != nil {
+		return d.Default(lang)
+	}
+	return lang
+}
+
+// Canonical converts a code reported by the provider back to canonical form.
+func (d Dialect) Canonical(code string) string {
+	for lang, c := range d.Codes {
+		if strings.EqualFold(c, code) {
+			return lang
+		}
+	}
+	return Normalize(code)
+}
@@ -0,0 +1,130 @@
+package translator
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"gopkg.in/ini.v1"
+)
+
+// Provider describes a translator that can be enabled from config.ini.
+type Provider struct {
+	// Name is both the route path and the config section, e.g. "deepl".
+	Name string
+	// Title is the human readable name printed at startup.
+	Title string
+	// New creates the translator from its config section.
+	New func(cfg *ini.Section) (Translator, error)
+}
+
+var providers []Provider
+
+// Register makes a provider available to Setup. It is meant to be called
+// from the init function of the provider package.
+func Register(p Provider) {
+	for _, registered := range providers {
+		if registered.Name == p.Name {
+			panic("translator: provider registered twice: " + p.Name)
+		}
+	}
+	providers = append(providers, p)
+}
+
+// Router is implemented by providers that serve routes besides /<name>. The
+// routes are registered under the /<name> group.
+type Router interface {
+	Routes(group *gin.RouterGroup)
+}
+
+// RootRouter is implemented by providers that also serve routes outside the
+// /<name> group, such as API compatible endpoints.
+type RootRouter interface {
+	RootRoutes(route *gin.Engine)
+}
+
+// entry is an enabled provider together with its default languages.
+// detector is nil for providers without language detection.
+type entry struct {
+	Provider
+	translator Translator
+	detector   Detector
+	defaults   Request
+}
+
+var enabled []*entry
+
+// Setup creates every enabled provider and registers its route.
+func Setup(route *gin.Engine, cfg *ini.File) {
+	cacheStore := setupCache(route, cfg.Section("cache"))
+	for _, p := range providers {
+		section := cfg.Section(p.Name)
+		if !section.Key("enable").MustBool() {
+			fmt.Printf("Dict: %s Disabled\n", p.Title)
+			continue
+		}
+		t, err := p.New(section)
+		if err != nil {
+			fmt.Printf("Dict: %s Failed: %v\n", p.Title, err)
+			continue
+		}
+		fmt.Printf("Dict: %s Enabled\n", p.Title)
+		if r, ok := t.(Router); ok {
+			r.Routes(route.Group("/" + p.Name))
+		}
+		if r, ok := t.(RootRouter); ok {
+			r.RootRoutes(route)
+		}
+		detector, _ := t.(Detector)
+		if cacheStore != nil {
+			t = &cached{name: p.Name, next: t, store: cacheStore}
+		}
+		e := &entry{
+			Provider:   p,
+			translator: t,
+			detector:   detector,
+			defaults:   Defaults(section),
+		}
+		enabled = append(enabled, e)
+		route.GET("/"+p.Name, handler(e))
+		route.GET("/"+p.Name+"/stream", streamHandler(e))
+		route.POST("/"+p.Name+"/batch", batchHandler(e))
+		if e.detector != nil {
+			route.GET("/"+p.Name+"/detect", detectHandler(e))
+		}
+	}
+	setupAll(route, cfg.Section("all"))
+	setupDeepLAPI(route, cfg.Section("deepl_api"))
+	setupChatAPI(route, cfg.Section("openai_api"))
+}
+
+// Defaults returns the default languages configured in a provider section.
+func Defaults(cfg *ini.Section) Request {
+	return Request{
+		SourceLang: Normalize(cfg.Key("source_lang").String()),
+		TargetLang: Normalize(cfg.Key("target_lang").MustString("zh")),
+	}
+}
+
+// RequestFromQuery builds a request from the gdword, sl, tl, style, context
+// and text_format query parameters, falling back to the provider defaults
+// for missing languages.
+func RequestFromQuery(c *gin.Context, defaults Request) Request {
+	return Request{
+		SourceLang: Normalize(c.DefaultQuery("sl", defaults.SourceLang)),
+		TargetLang: Normalize(c.DefaultQuery("tl", defaults.TargetLang)),
+		Text:       c.Query("gdword"),
+		Style:      c.Query("style"),
+		Context:    c.Query("context"),
+		Format:     c.Query("text_format"),
+	}
+}
+
+func handler(e *entry) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		req := RequestFromQuery(c, e.defaults)
+		start := time.Now()
+		result, err := Translate(c.Request.Context(), e.Name, e.translator, req)
+		render(c, OutputFormat(c, FormatHTML), req, result, err, time.Since(start))
+	}
+}
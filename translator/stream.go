@@ -0,0 +1,82 @@
+package translator
+
+import (
+	"context"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Streamer is implemented by providers that can deliver a translation while
+// it is generated. emit is called with each new piece of text and stops the
+// stream when it returns an error.
+type Streamer interface {
+	TranslateStream(ctx context.Context, req Request, emit func(delta string) error) (Result, error)
+}
+
+// TranslateStream is the streaming variant of Translate. Providers without
+// streaming support emit their whole translation at once.
+func TranslateStream(ctx context.Context, name string, t Translator, req Request, emit func(delta string) error) (Result, error) {
+	s, ok := t.(Streamer)
+	if !ok {
+		result, err := Translate(ctx, name, t, req)
+		if err == nil {
+			err = emit(result.Text)
+		}
+		return result, err
+	}
+
+	if strings.TrimSpace(req.Text) == "" {
+		return Result{Provider: name}, ErrNoText
+	}
+	result, err := s.TranslateStream(ctx, req, emit)
+	result.Provider = name
+	if err == nil && result.Text == "" {
+		err = ErrNoTranslation
+	}
+	return result, err
+}
+
+// TranslateStream serves cached translations at once and caches streamed
+// ones when they complete.
+func (c *cached) TranslateStream(ctx context.Context, req Request, emit func(delta string) error) (Result, error) {
+	key := cacheKey(c.name, req)
+	if result, ok := c.store.get(key); ok {
+		return result, emit(result.Text)
+	}
+	result, err := TranslateStream(ctx, c.name, c.next, req, emit)
+	if err == nil && result.Text != "" {
+		c.store.set(key, result)
+	}
+	return result, err
+}
+
+// streamHandler serves /<name>/stream as Server-Sent Events: "delta" events
+// carry pieces of the translation, followed by a "result" event with the
+// complete Result or an "error" event. The provider is cancelled when the
+// client disconnects.
+func streamHandler(e *entry) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		req := RequestFromQuery(c, e.defaults)
+		ctx := c.Request.Context()
+
+		c.Header("Cache-Control", "no-cache")
+		c.Header("X-Accel-Buffering", "no")
+		c.Status(http.StatusOK)
+		result, err := TranslateStream(ctx, e.Name, e.translator, req, func(delta string) error {
+			c.SSEvent("delta", delta)
+			c.Writer.Flush()
+			return ctx.Err()
+		})
+		if ctx.Err() != nil {
+			return
+		}
+		if err != nil {
+			c.SSEvent("error", ErrorResponse(err, e.Name))
+		} else {
+			c.SSEvent("result", result)
+		}
+		c.Writer.Flush()
+	}
+}
@@ -0,0 +1,87 @@
+package translator
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+// Request describes a single translation lookup. Languages are canonical
+// codes as returned by Normalize; an empty SourceLang asks the provider to
+// detect the language. Style and Context are hints for providers that can
+// adapt their output, such as the prompt template of LLM providers. Format
+// is "html" when Text is HTML markup to be translated as such, set with the
+// text_format query parameter.
+type Request struct {
+	SourceLang string
+	TargetLang string
+	Text       string
+	Style      string
+	Context    string
+	Format     string
+}
+
+// Result is the structured outcome of a translation shared by all providers.
+// SourceLang holds the detected language when the request did not set one.
+// Entry is only set by providers with dictionary data for single words.
+type Result struct {
+	Provider     string   `json:"provider"`
+	Text         string   `json:"text"`
+	Alternatives []string `json:"alternatives,omitempty"`
+	SourceLang   string   `json:"source_lang"`
+	TargetLang   string   `json:"target_lang"`
+	Entry        *Entry   `json:"entry,omitempty"`
+}
+
+// Translator is implemented by every dictionary provider.
+type Translator interface {
+	Translate(ctx context.Context, req Request) (Result, error)
+}
+
+// Error carries the HTTP status a provider failure should be reported with.
+type Error struct {
+	Code    int
+	Message string
+}
+
+func (e *Error) Error() string {
+	return e.Message
+}
+
+// ErrNoText is returned when a lookup has nothing to translate.
+var ErrNoText = &Error{Code: http.StatusNotFound, Message: "No text to translate"}
+
+// ErrNoTranslation is returned when a provider answers with an empty result.
+var ErrNoTranslation = &Error{Code: http.StatusNotFound, Message: "No Translation"}
+
+// Errorf formats a provider failure as a service unavailable error.
+func Errorf(format string, args ...any) error {
+	return &Error{Code: http.StatusServiceUnavailable, Message: fmt.Sprintf(format, args...)}
+}
+
+// StatusCode returns the HTTP status an error should be reported with.
+func StatusCode(err error) int {
+	if e, ok := err.(*Error); ok {
+		return e.Code
+	}
+	return http.StatusServiceUnavailable
+}
+
+// Translate runs a lookup through t and stamps the result with the provider
+// name, so empty input and empty output are reported the same way by every
+// provider.
+func Translate(ctx context.Context, name string, t Translator, req Request) (Result, error) {
+	if strings.TrimSpace(req.Text) == "" {
+		return Result{Provider: name}, ErrNoText
+	}
+	result, err := t.Translate(ctx, req)
+	result.Provider = name
+	if err != nil {
+		return result, err
+	}
+	if result.Text == "" {
+		return result, ErrNoTranslation
+	}
+	return result, nil
+}
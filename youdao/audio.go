@@ -0,0 +1,127 @@
+package youdao
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/yangxin0/gd-website-api/translator"
+)
+
+// audioHandler serves /youdao/audio?gdword=&side=src|tgt, which plays the
+// source text or its translation using Youdao's text to speech.
+func (t *Translator) audioHandler(c *gin.Context) {
+	req := translator.RequestFromQuery(c, t.defaults)
+	if req.Text == "" {
+		c.String(http.StatusNotFound, translator.ErrNoText.Error())
+		return
+	}
+	side := c.DefaultQuery("side", "src")
+	if side != "src" && side != "tgt" {
+		c.String(http.StatusBadRequest, "side must be src or tgt")
+		return
+	}
+
+	path := audioPath(t.audioDir, side, req)
+	if _, err := os.Stat(path); err != nil {
+		if err := t.fetchAudio(c.Request.Context(), path, side, req); err != nil {
+			c.String(translator.StatusCode(err), err.Error())
+			return
+		}
+	}
+	c.Header("Content-Type", "audio/mpeg")
+	c.File(path)
+}
+
+// audioClient downloads the speech files linked by Youdao.
+var audioClient = &http.Client{Timeout: 10 * time.Second}
+
+// fetchAudio downloads the speech of one side of the translation to path.
+func (t *Translator) fetchAudio(ctx context.Context, path string, side string, req translator.Request) error {
+	translation, err := Translate(dialect.Code(req.SourceLang), dialect.Code(req.TargetLang), req.Text)
+	if err != nil {
+		return err
+	}
+	speakURL := translation.SpeakURL
+	if side == "tgt" {
+		speakURL = translation.TSpeakURL
+	}
+	if speakURL == "" {
+		return translator.ErrNoTranslation
+	}
+	return downloadAudio(ctx, speakURL, path)
+}
+
+// downloadAudio saves the audio at speakURL to path. The URL is requested as
+// is, since its query carries Youdao's signature.
+func downloadAudio(ctx context.Context, speakURL string, path string) error {
+	request, err := http.NewRequestWithContext(ctx, "GET", speakURL, nil)
+	if err != nil {
+		return translator.Errorf("%v", err)
+	}
+	resp, err := audioClient.Do(request)
+	if err != nil {
+		return translator.Errorf("Youdao audio request failed: %v", err)
+	}
+	defer resp.Body.Close()
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return translator.Errorf("Youdao audio request failed: %v", err)
+	}
+	if resp.StatusCode != http.StatusOK || !strings.HasPrefix(resp.Header.Get("Content-Type"), "audio/") {
+		return translator.Errorf("Youdao audio request failed: %s", data)
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return translator.Errorf("%v", err)
+	}
+	if err := writeFile(path, data); err != nil {
+		return translator.Errorf("%v", err)
+	}
+	return nil
+}
+
+// writeFile writes data to a temporary file in the directory of path and
+// renames it into place, so a failed write is never served from the cache
+// and concurrent misses never truncate a file that is being served.
+func writeFile(path string, data []byte) error {
+	f, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".*.tmp")
+	if err != nil {
+		return err
+	}
+	_, err = f.Write(data)
+	if closeErr := f.Close(); err == nil {
+		err = closeErr
+	}
+	if err == nil {
+		err = os.Rename(f.Name(), path)
+	}
+	if err != nil {
+		os.Remove(f.Name())
+	}
+	return err
+}
+
+// audioPath names the cached MP3 after the side, languages and text.
+func audioPath(dir string, side string, req translator.Request) string {
+	sum := sha256.Sum256([]byte(side + "\x1f" + req.SourceLang + "\x1f" + req.TargetLang + "\x1f" + req.Text))
+	return filepath.Join(dir, hex.EncodeToString(sum[:])+".mp3")
+}
+
+// audioURL links to the audio route for one side of req.
+func audioURL(side string, req translator.Request) string {
+	query := url.Values{
+		"gdword": {req.Text},
+		"side":   {side},
+		"sl":     {req.SourceLang},
+		"tl":     {req.TargetLang},
+	}
+	return "/youdao/audio?" + query.Encode()
+}
@@ -0,0 +1,48 @@
+package youdao
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+
+	"github.com/yangxin0/gd-website-api/translator"
+)
+
+func TestDownloadAudio(t *testing.T) {
+	mp3 := []byte("ID3\x03\x00fake mp3")
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		query := r.URL.Query()
+		if query.Get("q") != "good" || query.Get("sign") != "abc" || query.Get("appKey") != "k" {
+			w.Header().Set("Content-Type", "application/json")
+			w.Write([]byte(`{"errorCode":"202"}`))
+			return
+		}
+		w.Header().Set("Content-Type", "audio/mpeg")
+		w.Write(mp3)
+	}))
+	defer server.Close()
+
+	req := translator.Request{SourceLang: "en", TargetLang: "zh", Text: "good"}
+	path := audioPath(t.TempDir()+"/audio", "src", req)
+	if err := downloadAudio(context.Background(), server.URL+"/speak?q=good&langType=en&sign=abc&salt=1&appKey=k", path); err != nil {
+		t.Fatalf("downloadAudio() = %v", err)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(data) != string(mp3) {
+		t.Errorf("saved %q, want %q", data, mp3)
+	}
+
+	// Without the signed query Youdao answers with JSON, which is not cached.
+	path = audioPath(t.TempDir(), "tgt", req)
+	if err := downloadAudio(context.Background(), server.URL+"/speak", path); err == nil {
+		t.Error("downloadAudio() without signature succeeded")
+	}
+	if _, err := os.Stat(path); !os.IsNotExist(err) {
+		t.Errorf("failed download left %s behind", path)
+	}
+}
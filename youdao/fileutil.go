@@ -16,6 +16,7 @@ func SaveFile(path string, data []byte, needDecode bool) {
 	if err != nil {
 		fmt.Print("file create failed. err: " + err.Error())
 	} else {
+		defer file.Close()
 		file.Write(data)
 	}
 }
@@ -45,8 +45,12 @@ func DoGet(url string, header map[string][]string, paramsMap map[string][]string
 }
 
 func DoPost(url string, header map[string][]string, bodyMap map[string][]string, expectContentType string) []byte {
+	return DoPostWithTimeout(url, header, bodyMap, expectContentType, time.Second*3)
+}
+
+func DoPostWithTimeout(url string, header map[string][]string, bodyMap map[string][]string, expectContentType string, timeout time.Duration) []byte {
 	client := &http.Client{
-		Timeout: time.Second * 3,
+		Timeout: timeout,
 	}
 	params := neturl.Values{}
 	for k, v := range bodyMap {
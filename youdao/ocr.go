@@ -0,0 +1,178 @@
+package youdao
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/yangxin0/gd-website-api/translator"
+	"github.com/yangxin0/gd-website-api/youdao/authv3"
+)
+
+// OCRResult is the response of the Youdao image translation API.
+type OCRResult struct {
+	ErrorCode   string      `json:"errorCode"`
+	Orientation string      `json:"orientation"`
+	LanFrom     string      `json:"lanFrom"`
+	LanTo       string      `json:"lanTo"`
+	ResRegions  []OCRRegion `json:"resRegions"`
+}
+
+// OCRRegion is a block of recognized text and its translation.
+// BoundingBox is "x,y,width,height" in image pixels.
+type OCRRegion struct {
+	BoundingBox string `json:"boundingBox"`
+	LinesCount  int    `json:"linesCount"`
+	Context     string `json:"context"`
+	TranContent string `json:"tranContent"`
+}
+
+// Region is a recognized text region as returned by /youdao/ocr.
+type Region struct {
+	BoundingBox string `json:"bounding_box"`
+	Text        string `json:"text"`
+	Translation string `json:"translation"`
+}
+
+// TranslateImage recognizes and translates the text of a base64 encoded
+// image.
+func TranslateImage(sourceLang string, targetLang string, image string) (*OCRResult, error) {
+	if sourceLang == "" {
+		sourceLang = "auto"
+	}
+	if targetLang == "" {
+		targetLang = "auto"
+	}
+	params := map[string][]string{
+		"type":   {"1"},
+		"from":   {sourceLang},
+		"to":     {targetLang},
+		"render": {"0"},
+		"q":      {image},
+	}
+	header := map[string][]string{
+		"Content-Type": {"application/x-www-form-urlencoded"},
+	}
+
+	authv3.AddAuthParams(appKey, appSecret, params)
+	body := DoPostWithTimeout("https://openapi.youdao.com/ocrtransapi", header, params, "application/json", time.Second*30)
+	if body == nil {
+		return nil, translator.Errorf("Youdao OCR request failed")
+	}
+	var result OCRResult
+	if err := json.Unmarshal(body, &result); err != nil {
+		return nil, translator.Errorf("Youdao OCR returns an invalid result: %v", err)
+	}
+	if result.ErrorCode != "0" {
+		return nil, translator.Errorf("Youdao OCR error code %s", result.ErrorCode)
+	}
+	return &result, nil
+}
+
+// ocrHandler serves POST /youdao/ocr. The image is either a multipart file
+// named "file" or a base64 string in the "img" form or JSON field. The
+// regions are returned as JSON by default, or in the format chosen by the
+// format parameter or the Accept header.
+func (t *Translator) ocrHandler(c *gin.Context) {
+	format := translator.OutputFormat(c, translator.FormatJSON)
+	image, err := readImage(c)
+	if err != nil {
+		ocrError(c, format, &translator.Error{Code: http.StatusBadRequest, Message: err.Error()})
+		return
+	}
+	sourceLang := translator.Normalize(c.DefaultQuery("sl", t.defaults.SourceLang))
+	targetLang := translator.Normalize(c.DefaultQuery("tl", t.defaults.TargetLang))
+
+	result, err := TranslateImage(dialect.Code(sourceLang), dialect.Code(targetLang), image)
+	if err != nil {
+		ocrError(c, format, err)
+		return
+	}
+
+	regions := make([]Region, 0, len(result.ResRegions))
+	for _, r := range result.ResRegions {
+		regions = append(regions, Region{
+			BoundingBox: r.BoundingBox,
+			Text:        r.Context,
+			Translation: r.TranContent,
+		})
+	}
+	response := gin.H{
+		"code":        http.StatusOK,
+		"source_lang": dialect.Canonical(result.LanFrom),
+		"target_lang": dialect.Canonical(result.LanTo),
+		"regions":     regions,
+	}
+	switch format {
+	case translator.FormatHTML:
+		c.HTML(http.StatusOK, "ocr.tmpl", response)
+	case translator.FormatText, translator.FormatMarkdown:
+		var b strings.Builder
+		for _, r := range regions {
+			if format == translator.FormatMarkdown {
+				fmt.Fprintf(&b, "- %s — %s\n", r.Text, r.Translation)
+			} else {
+				b.WriteString(r.Translation + "\n")
+			}
+		}
+		if format == translator.FormatMarkdown {
+			c.Data(http.StatusOK, "text/markdown; charset=utf-8", []byte(b.String()))
+		} else {
+			c.String(http.StatusOK, b.String())
+		}
+	default:
+		c.JSON(http.StatusOK, response)
+	}
+}
+
+// ocrError reports err with the payload of the other provider routes.
+func ocrError(c *gin.Context, format string, err error) {
+	if format == translator.FormatJSON {
+		c.JSON(translator.StatusCode(err), translator.ErrorResponse(err, "youdao"))
+		return
+	}
+	c.String(translator.StatusCode(err), err.Error())
+}
+
+// readImage returns the uploaded image as base64.
+func readImage(c *gin.Context) (string, error) {
+	if file, err := c.FormFile("file"); err == nil {
+		f, err := file.Open()
+		if err != nil {
+			return "", err
+		}
+		defer f.Close()
+		data, err := io.ReadAll(f)
+		if err != nil {
+			return "", err
+		}
+		return base64.StdEncoding.EncodeToString(data), nil
+	}
+
+	image := c.PostForm("img")
+	if image == "" && strings.HasPrefix(c.ContentType(), gin.MIMEJSON) {
+		var body struct {
+			Img string `json:"img"`
+		}
+		if err := c.ShouldBindJSON(&body); err != nil {
+			return "", err
+		}
+		image = body.Img
+	}
+	// Accept data URIs such as "data:image/png;base64,..."
+	if _, data, found := strings.Cut(image, ";base64,"); found {
+		image = data
+	}
+	if image == "" {
+		return "", fmt.Errorf("No image to translate")
+	}
+	if _, err := base64.StdEncoding.DecodeString(image); err != nil {
+		return "", fmt.Errorf("Invalid base64 image: %v", err)
+	}
+	return image, nil
+}
@@ -0,0 +1,262 @@
+package youdao
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gorilla/websocket"
+	"github.com/yangxin0/gd-website-api/translator"
+	"github.com/yangxin0/gd-website-api/youdao/authv4"
+)
+
+const speechURL = "wss://openapi.youdao.com/stream_speech_trans"
+
+// speechChunkSize is 200ms of 16kHz 16-bit mono audio, the chunk size used
+// by Youdao's streaming demos.
+const speechChunkSize = 6400
+
+// Transcript is a partial or final result of the speech translation stream.
+type Transcript struct {
+	SegID       int    `json:"seg_id"`
+	Partial     bool   `json:"partial"`
+	Text        string `json:"text"`
+	Translation string `json:"translation"`
+}
+
+type speechMessage struct {
+	Action    string `json:"action"`
+	ErrorCode string `json:"errorCode"`
+	Result    struct {
+		SegID       int    `json:"segId"`
+		Partial     bool   `json:"partial"`
+		Context     string `json:"context"`
+		TranContent string `json:"tranContent"`
+	} `json:"result"`
+}
+
+// SpeechSession relays audio to Youdao's streaming speech translation
+// service.
+type SpeechSession struct {
+	ws       *websocket.Conn
+	messages <-chan []byte
+}
+
+// NewSpeechSession opens a speech translation stream for audio in format
+// ("wav" or "pcm") sampled at rate Hz.
+func NewSpeechSession(sourceLang string, targetLang string, format string, rate int) (*SpeechSession, error) {
+	params := map[string][]string{
+		"from":    {sourceLang},
+		"to":      {targetLang},
+		"format":  {format},
+		"rate":    {strconv.Itoa(rate)},
+		"channel": {"1"},
+		"version": {"v1"},
+	}
+	authv4.AddAuthParams(appKey, appSecret, params)
+	ws, messages, err := InitConnectionWithParams(speechURL, params)
+	if err != nil {
+		return nil, translator.Errorf("%v", err)
+	}
+	return &SpeechSession{ws: ws, messages: messages}, nil
+}
+
+// Send relays one chunk of audio.
+func (s *SpeechSession) Send(chunk []byte) error {
+	return SendBinaryMessage(s.ws, chunk)
+}
+
+// End tells the service that no more audio follows.
+func (s *SpeechSession) End() error {
+	return SendTextMessage(s.ws, `{"end": "true"}`)
+}
+
+// Next returns the next transcript, or io.EOF once the service closed the
+// stream.
+func (s *SpeechSession) Next() (Transcript, error) {
+	for msg := range s.messages {
+		var m speechMessage
+		if err := json.Unmarshal(msg, &m); err != nil {
+			return Transcript{}, fmt.Errorf("invalid speech message: %v", err)
+		}
+		if m.ErrorCode != "0" {
+			return Transcript{}, fmt.Errorf("Youdao speech error code %s", m.ErrorCode)
+		}
+		if m.Action != "recognition" {
+			continue
+		}
+		return Transcript{
+			SegID:       m.Result.SegID,
+			Partial:     m.Result.Partial,
+			Text:        m.Result.Context,
+			Translation: m.Result.TranContent,
+		}, nil
+	}
+	return Transcript{}, io.EOF
+}
+
+// Close closes the stream and discards unread messages.
+func (s *SpeechSession) Close() error {
+	err := s.ws.Close()
+	go func() {
+		for range s.messages {
+		}
+	}()
+	return err
+}
+
+// speechParams reads the languages, audio format and rate of a speech
+// request. The audio format is audio_format, as format selects the output.
+func (t *Translator) speechParams(c *gin.Context) (string, string, string, int) {
+	sourceLang := translator.Normalize(c.DefaultQuery("sl", t.defaults.SourceLang))
+	targetLang := translator.Normalize(c.DefaultQuery("tl", t.defaults.TargetLang))
+	if sourceLang == "" {
+		sourceLang = "en"
+	}
+	rate, err := strconv.Atoi(c.DefaultQuery("rate", "16000"))
+	if err != nil {
+		rate = 16000
+	}
+	return dialect.Code(sourceLang), dialect.Code(targetLang), c.DefaultQuery("audio_format", "wav"), rate
+}
+
+// speechHandler serves POST /youdao/speech. The body (or multipart file
+// "file") is WAV or PCM audio; transcripts are streamed back as Server-Sent
+// Events named "transcript", followed by "done" or "error".
+func (t *Translator) speechHandler(c *gin.Context) {
+	audio := io.Reader(c.Request.Body)
+	if file, err := c.FormFile("file"); err == nil {
+		f, err := file.Open()
+		if err != nil {
+			c.String(http.StatusBadRequest, err.Error())
+			return
+		}
+		defer f.Close()
+		audio = f
+	}
+
+	session, err := NewSpeechSession(t.speechParams(c))
+	if err != nil {
+		c.String(translator.StatusCode(err), err.Error())
+		return
+	}
+
+	// The relay reads the request body, which must not be used once the
+	// handler returns: stop it and wait for it before the deferred f.Close.
+	// Closing the session unblocks a pending Send.
+	ctx, cancel := context.WithCancel(c.Request.Context())
+	relayed := make(chan struct{})
+	go func() {
+		defer close(relayed)
+		relayAudio(ctx, session, audio)
+	}()
+	defer func() {
+		cancel()
+		session.Close()
+		<-relayed
+	}()
+
+	c.Stream(func(w io.Writer) bool {
+		transcript, err := session.Next()
+		if err == io.EOF {
+			c.SSEvent("done", "")
+			return false
+		}
+		if err != nil {
+			c.SSEvent("error", err.Error())
+			return false
+		}
+		c.SSEvent("transcript", transcript)
+		return ctx.Err() == nil
+	})
+}
+
+// relayAudio sends audio in real time sized chunks, as Youdao's streaming
+// demos do, followed by the end marker.
+func relayAudio(ctx context.Context, session *SpeechSession, audio io.Reader) {
+	chunk := make([]byte, speechChunkSize)
+	ticker := time.NewTicker(200 * time.Millisecond)
+	defer ticker.Stop()
+	for {
+		n, err := io.ReadFull(audio, chunk)
+		if n > 0 {
+			if session.Send(chunk[:n]) != nil {
+				return
+			}
+		}
+		if err != nil {
+			break
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+	session.End()
+}
+
+var upgrader = websocket.Upgrader{
+	CheckOrigin: func(r *http.Request) bool {
+		return true
+	},
+}
+
+// speechSocketHandler serves the /youdao/speech/ws websocket. Binary
+// messages from the client are relayed as audio and a text message
+// {"end": "true"} ends the stream; transcripts are sent back as JSON text
+// messages.
+func (t *Translator) speechSocketHandler(c *gin.Context) {
+	sourceLang, targetLang, format, rate := t.speechParams(c)
+	client, err := upgrader.Upgrade(c.Writer, c.Request, nil)
+	if err != nil {
+		return
+	}
+	defer client.Close()
+
+	session, err := NewSpeechSession(sourceLang, targetLang, format, rate)
+	if err != nil {
+		client.WriteJSON(gin.H{"error": err.Error()})
+		return
+	}
+	defer session.Close()
+
+	go func() {
+		for {
+			msgType, msg, err := client.ReadMessage()
+			if err != nil {
+				session.Close()
+				return
+			}
+			if msgType == websocket.BinaryMessage {
+				err = session.Send(msg)
+			} else {
+				err = session.End()
+			}
+			if err != nil {
+				return
+			}
+		}
+	}()
+
+	for {
+		transcript, err := session.Next()
+		if err == io.EOF {
+			client.WriteMessage(websocket.CloseMessage,
+				websocket.FormatCloseMessage(websocket.CloseNormalClosure, ""))
+			return
+		}
+		if err != nil {
+			client.WriteJSON(gin.H{"error": err.Error()})
+			return
+		}
+		if client.WriteJSON(transcript) != nil {
+			return
+		}
+	}
+}
@@ -1,11 +1,12 @@
 package youdao
 
 import (
+	"context"
 	"encoding/json"
-	"fmt"
-	"net/http"
+	"strings"
 
 	"github.com/gin-gonic/gin"
+	"github.com/yangxin0/gd-website-api/translator"
 	"github.com/yangxin0/gd-website-api/youdao/authv3"
 	"gopkg.in/ini.v1"
 )
@@ -13,36 +14,153 @@ import (
 var appKey = ""
 var appSecret = ""
 
+// Translation is the response of the Youdao text translation API. Basic and
+// Web are only returned for single words.
 type Translation struct {
-    Texts []string `json:"translation"`
+	ErrorCode    string   `json:"errorCode"`
+	Query        string   `json:"query"`
+	Texts        []string `json:"translation"`
+	Basic        *Basic   `json:"basic"`
+	Web          []Web    `json:"web"`
+	L            string   `json:"l"`
+	IsWord       bool     `json:"isWord"`
+	ReturnPhrase []string `json:"returnPhrase"`
+	SpeakURL     string   `json:"speakUrl"`
+	TSpeakURL    string   `json:"tSpeakUrl"`
 }
 
-func TranslateInit(route *gin.Engine, cfg *ini.File) {
-    enabled := cfg.Section("youdao").Key("enable").MustBool()
-    if enabled == false {
-        fmt.Println("Dict: Youdao Disabled")
-        return
-    }
-    fmt.Println("Dict: Youdao Enabled")
-    appKey = cfg.Section("youdao").Key("app_key").String()
-    appSecret = cfg.Section("youdao").Key("app_secret").String()
-    route.GET("/youdao", func(c *gin.Context){
-        text := c.Query("gdword")
-        result := Translate("", "zh-CHS", text)
-        if result != "" {
-            c.HTML(http.StatusOK, "goldendict.tmpl", gin.H{
-                "Text": result,
-            })
-        } else {
-            c.String(http.StatusNotFound, "No Translation")
-        }
-    })
+// Basic holds the dictionary definition of a word.
+type Basic struct {
+	Phonetic   string     `json:"phonetic"`
+	UKPhonetic string     `json:"uk-phonetic"`
+	USPhonetic string     `json:"us-phonetic"`
+	UKSpeech   string     `json:"uk-speech"`
+	USSpeech   string     `json:"us-speech"`
+	Explains   []string   `json:"explains"`
+	Wfs        []WordForm `json:"wfs"`
+}
+
+// WordForm is an inflection such as the plural or past tense.
+type WordForm struct {
+	Wf struct {
+		Name  string `json:"name"`
+		Value string `json:"value"`
+	} `json:"wf"`
+}
+
+// Web is a phrase found on the web together with its translations.
+type Web struct {
+	Key   string   `json:"key"`
+	Value []string `json:"value"`
+}
+
+func init() {
+	translator.Register(translator.Provider{
+		Name:  "youdao",
+		Title: "Youdao",
+		New:   New,
+	})
+}
+
+// dialect maps canonical codes to Youdao's codes, which use zh-CHS/zh-CHT
+// for Chinese and plain primary subtags for every other language.
+var dialect = translator.Dialect{
+	Codes: map[string]string{
+		"zh":    "zh-CHS",
+		"zh-TW": "zh-CHT",
+	},
+	Default: func(lang string) string {
+		return strings.Split(lang, "-")[0]
+	},
 }
 
-func Translate(sourceLang string, targetLang string, Text string) string {
+// Translator uses the Youdao text translation API.
+type Translator struct {
+	defaults translator.Request
+	audioDir string
+}
+
+func New(cfg *ini.Section) (translator.Translator, error) {
+	appKey = cfg.Key("app_key").String()
+	appSecret = cfg.Key("app_secret").String()
+	return &Translator{
+		defaults: translator.Defaults(cfg),
+		audioDir: cfg.Key("audio_dir").MustString("audio"),
+	}, nil
+}
+
+// Routes registers the audio, image and speech translation routes under
+// /youdao.
+func (t *Translator) Routes(group *gin.RouterGroup) {
+	group.GET("/audio", t.audioHandler)
+	group.POST("/ocr", t.ocrHandler)
+	group.POST("/speech", t.speechHandler)
+	group.GET("/speech/ws", t.speechSocketHandler)
+}
+
+func (t *Translator) Translate(ctx context.Context, req translator.Request) (translator.Result, error) {
+	translation, err := Translate(dialect.Code(req.SourceLang), dialect.Code(req.TargetLang), req.Text)
+	if err != nil {
+		return translator.Result{}, err
+	}
+	result := translator.Result{
+		SourceLang: req.SourceLang,
+		TargetLang: req.TargetLang,
+	}
+	if len(translation.Texts) > 0 {
+		result.Text = translation.Texts[0]
+	}
+	// l is "<from>2<to>", e.g. "en2zh-CHS"
+	if from, _, found := strings.Cut(translation.L, "2"); found && result.SourceLang == "" {
+		result.SourceLang = dialect.Canonical(from)
+	}
+	if translator.IsWord(req.Text) && translation.Basic != nil {
+		result.Entry = toEntry(req.Text, translation)
+		if translation.SpeakURL != "" {
+			result.Entry.Audio = append(result.Entry.Audio, translator.Audio{Label: "Source", URL: audioURL("src", req)})
+		}
+		if translation.TSpeakURL != "" {
+			result.Entry.Audio = append(result.Entry.Audio, translator.Audio{Label: "Translation", URL: audioURL("tgt", req)})
+		}
+	}
+	return result, nil
+}
+
+// toEntry converts the basic and web fields into a dictionary entry.
+func toEntry(word string, translation *Translation) *translator.Entry {
+	basic := translation.Basic
+	entry := &translator.Entry{Word: word}
+	if len(translation.ReturnPhrase) > 0 {
+		entry.Word = translation.ReturnPhrase[0]
+	}
+	if basic.USPhonetic != "" {
+		entry.Phonetics = append(entry.Phonetics, translator.Phonetic{Label: "US", IPA: basic.USPhonetic})
+	}
+	if basic.UKPhonetic != "" {
+		entry.Phonetics = append(entry.Phonetics, translator.Phonetic{Label: "UK", IPA: basic.UKPhonetic})
+	}
+	if len(entry.Phonetics) == 0 && basic.Phonetic != "" {
+		entry.Phonetics = append(entry.Phonetics, translator.Phonetic{IPA: basic.Phonetic})
+	}
+	for _, explain := range basic.Explains {
+		entry.Senses = append(entry.Senses, translator.ParseSense(explain))
+	}
+	for _, wfs := range basic.Wfs {
+		entry.Forms = append(entry.Forms, translator.Form{Name: wfs.Wf.Name, Value: wfs.Wf.Value})
+	}
+	for _, web := range translation.Web {
+		entry.Phrases = append(entry.Phrases, translator.Phrase{Text: web.Key, Meanings: web.Value})
+	}
+	return entry
+}
+
+func Translate(sourceLang string, targetLang string, Text string) (*Translation, error) {
     if sourceLang == "" {
         sourceLang = "auto"
     }
+    if targetLang == "" {
+        targetLang = "auto"
+    }
     params := map[string][]string{
         "from": { sourceLang },
         "to": { targetLang },
@@ -56,11 +174,14 @@ func Translate(sourceLang string, targetLang string, Text string) string {
 	authv3.AddAuthParams(appKey, appSecret, params)
 	body := DoPost("https://openapi.youdao.com/api", header, params, "application/json")
     if body == nil {
-        return ""
+        return nil, translator.Errorf("Youdao API request failed")
     }
     var result Translation
-    if json.Unmarshal(body, &result) != nil {
-        return ""
+    if err := json.Unmarshal(body, &result); err != nil {
+        return nil, translator.Errorf("Youdao API returns an invalid result: %v", err)
+    }
+    if result.ErrorCode != "0" {
+        return nil, translator.Errorf("Youdao API error code %s", result.ErrorCode)
     }
-    return result.Texts[0]
+    return &result, nil
 }
@@ -2,39 +2,37 @@ package youdao
 
 import (
 	"fmt"
-	"github.com/gorilla/websocket"
 	neturl "net/url"
-	"os"
-	"strconv"
-	"strings"
-	"sync"
+
+	"github.com/gorilla/websocket"
 )
 
 /*
-初始化websocket连接
+初始化websocket连接, 服务端返回的text message通过channel传递, 连接断开时channel关闭
 */
-func InitConnection(url string) (*websocket.Conn, *sync.WaitGroup) {
+func InitConnection(url string) (*websocket.Conn, <-chan []byte, error) {
 	ws, _, err := websocket.DefaultDialer.Dial(url, nil)
 	if err != nil {
-		fmt.Println("connection failed.", err)
-		os.Exit(-1)
+		return nil, nil, fmt.Errorf("connection failed: %v", err)
 	}
-	wg := sync.WaitGroup{}
+	messages := make(chan []byte, 16)
 	// 监听返回数据
-	go messageHandler(ws, &wg)
-	wg.Add(1)
-	return ws, &wg
+	go messageHandler(ws, messages)
+	return ws, messages, nil
 }
 
 /*
 初始化websocket连接, 并附带参数
 */
-func InitConnectionWithParams(url string, paramsMap map[string][]string) (*websocket.Conn, *sync.WaitGroup) {
+func InitConnectionWithParams(url string, paramsMap map[string][]string) (*websocket.Conn, <-chan []byte, error) {
 	params := neturl.Values{}
 	for k, v := range paramsMap {
 		params[k] = v
 	}
-	parseUrl, _ := neturl.Parse(url)
+	parseUrl, err := neturl.Parse(url)
+	if err != nil {
+		return nil, nil, err
+	}
 	parseUrl.RawQuery = params.Encode()
 	return InitConnection(parseUrl.String())
 }
@@ -42,38 +40,29 @@ func InitConnectionWithParams(url string, paramsMap map[string][]string) (*webso
 /*
 发送binary message
 */
-func SendBinaryMessage(ws *websocket.Conn, message []byte) {
-	ws.WriteMessage(websocket.BinaryMessage, message)
-	fmt.Println("send binary message length: " + strconv.Itoa(len(message)))
+func SendBinaryMessage(ws *websocket.Conn, message []byte) error {
+	return ws.WriteMessage(websocket.BinaryMessage, message)
 }
 
 /*
 发送text message
 */
-func SendTextMessage(ws *websocket.Conn, message string) {
-	ws.WriteMessage(websocket.TextMessage, []byte(message))
-	fmt.Println("send text message: " + message)
+func SendTextMessage(ws *websocket.Conn, message string) error {
+	return ws.WriteMessage(websocket.TextMessage, []byte(message))
 }
 
-func messageHandler(ws *websocket.Conn, wg *sync.WaitGroup) {
+func messageHandler(ws *websocket.Conn, messages chan<- []byte) {
+	defer close(messages)
 	for {
 		msgType, msg, err := ws.ReadMessage()
 		if err != nil {
-			fmt.Println("message handler error ", err)
-			break
-		}
-		switch msgType {
-		case websocket.TextMessage:
-			message := string(msg)
-			fmt.Println("received text message: " + message)
-			if !strings.Contains(message, "\"errorCode\":\"0\"") {
-				wg.Done()
-				os.Exit(-1)
+			if !websocket.IsCloseError(err, websocket.CloseNormalClosure) {
+				fmt.Println("message handler error ", err)
 			}
-		case websocket.BinaryMessage:
-			fmt.Println("received binary message length: " + string(rune(len(msg))))
-		case websocket.CloseMessage:
-			fmt.Println("connection closed. " + string(msg))
+			return
+		}
+		if msgType == websocket.TextMessage {
+			messages <- msg
 		}
 	}
 }